@@ -0,0 +1,86 @@
+package derp
+
+import (
+	"fmt"
+	"math"
+)
+
+// False-positive rate DistinctApprox() sizes its Bloom filter for.
+const defaultFalsePositiveRate = 0.01
+
+// Drop duplicate elements (by key) using a Bloom filter sized for expected distinct keys, keeping first
+// occurrences in order. Memory stays bounded no matter how large in is.
+//
+// It is probabilistic: every duplicate is dropped, but roughly 1% of unique elements may be dropped too,
+// more if expected is an underestimate. Use it where exact dedup is infeasible.
+func DistinctApprox[T any](in []T, key func(T) uint64, expected int) []T {
+	out, _ := DistinctApproxRate(in, key, expected, defaultFalsePositiveRate)
+	return out
+}
+
+// Like DistinctApprox, with a chosen false-positive rate in (0, 1).
+func DistinctApproxRate[T any](in []T, key func(T) uint64, expected int, rate float64) ([]T, error) {
+	if !(rate > 0 && rate < 1) {
+		return nil, fmt.Errorf("DistinctApproxRate(): false-positive rate %v: %w (0, 1)", rate, ErrOutOfRange)
+	}
+
+	filter := newBloomFilter(max(expected, 1), rate)
+	out := make([]T, 0, min(len(in), max(expected, 0)))
+
+	for _, val := range in {
+		if filter.testAndAdd(key(val)) {
+			continue
+		}
+		out = append(out, val)
+	}
+
+	return out, nil
+}
+
+type bloomFilter struct {
+	bits   []uint64
+	size   uint64 // in bits
+	hashes int
+}
+
+// Size the filter with the standard optimum: m = -n·ln(p) / ln(2)², k = (m/n)·ln(2).
+func newBloomFilter(n int, rate float64) *bloomFilter {
+	size := uint64(math.Ceil(-float64(n) * math.Log(rate) / (math.Ln2 * math.Ln2)))
+	size = max(size, 64)
+
+	return &bloomFilter{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: max(int(math.Round(float64(size)/float64(n)*math.Ln2)), 1),
+	}
+}
+
+// Report whether key was (probably) seen before, then record it.
+func (filter *bloomFilter) testAndAdd(key uint64) bool {
+	// double hashing: probe i is h1 + i·h2
+	h1 := mix64(key)
+	h2 := mix64(h1) | 1
+
+	seen := true
+	for i := range filter.hashes {
+		bit := (h1 + uint64(i)*h2) % filter.size
+		word, mask := bit/64, uint64(1)<<(bit%64)
+
+		if filter.bits[word]&mask == 0 {
+			seen = false
+			filter.bits[word] |= mask
+		}
+	}
+
+	return seen
+}
+
+// splitmix64 finalizer; spreads poorly distributed keys (eg. sequential ids) across the filter
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
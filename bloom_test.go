@@ -0,0 +1,35 @@
+package derp
+
+import "testing"
+
+func TestDistinctApprox(t *testing.T) {
+	const unique = 10_000
+
+	// every key twice, second pass reversed
+	keys := make([]uint64, 0, 2*unique)
+	for idx := range unique {
+		keys = append(keys, uint64(idx))
+	}
+	for idx := range unique {
+		keys = append(keys, uint64(unique-1-idx))
+	}
+
+	gotten := DistinctApprox(keys, func(value uint64) uint64 { return value }, unique)
+
+	seen := make(map[uint64]bool)
+	for _, val := range gotten {
+		if seen[val] {
+			t.Fatalf("TestDistinctApprox(); duplicate [%v] kept", val)
+		}
+		seen[val] = true
+	}
+
+	// 1% false positives; allow some slack
+	if rate := float64(unique-len(gotten)) / unique; rate > 0.02 {
+		t.Errorf("TestDistinctApprox(); dropped too many unique elements.\nExpected: [<= 2%%] Got: [%.2f%%]\n", rate*100)
+	}
+
+	if _, err := DistinctApproxRate(keys, func(value uint64) uint64 { return value }, unique, 1); err == nil {
+		t.Errorf("TestDistinctApprox(); expected error for rate outside (0, 1)")
+	}
+}
@@ -0,0 +1,119 @@
+package derp
+
+import (
+	"container/list"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const defaultCacheSize = 32
+
+// least-recently-used store of Apply results
+type resultCache[T any] struct {
+	size    int
+	entries map[string]*list.Element
+	recency *list.List // front is most recently used
+}
+
+type cacheEntry[T any] struct {
+	key   string
+	value []T
+}
+
+func newResultCache[T any](size int) *resultCache[T] {
+	return &resultCache[T]{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		recency: list.New(),
+	}
+}
+
+func (cache *resultCache[T]) get(key string) ([]T, bool) {
+	elem, ok := cache.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	cache.recency.MoveToFront(elem)
+	return elem.Value.(*cacheEntry[T]).value, true
+}
+
+func (cache *resultCache[T]) put(key string, value []T) {
+	if elem, ok := cache.entries[key]; ok {
+		elem.Value.(*cacheEntry[T]).value = value
+		cache.recency.MoveToFront(elem)
+		return
+	}
+
+	cache.entries[key] = cache.recency.PushFront(&cacheEntry[T]{key: key, value: value})
+	cache.evict()
+}
+
+// drop least recently used entries until the cache fits its size
+func (cache *resultCache[T]) evict() {
+	for cache.recency.Len() > cache.size {
+		oldest := cache.recency.Back()
+		cache.recency.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*cacheEntry[T]).key)
+	}
+}
+
+// Like Apply(), but results are cached under keyer(input) and a hit skips the work entirely.
+//
+// Opt-in because it is only correct when every order is a pure function and inputs with the same key
+// hold the same data; the key must capture anything that changes the result, options included.
+// Hits return a deep clone, made by the CloneWith() hook if set, so callers can't corrupt the cache. Applies with Opt_Reset are not cached.
+// The cache holds the 32 most recently used results unless changed with SetCacheSize().
+func (pipeline *Pipeline[T]) ApplyCached(input []T, keyer func([]T) string, options ...Option) ([]T, error) {
+	start := time.Now()
+	key := keyer(input)
+
+	if pipeline.cache != nil {
+		if cached, ok := pipeline.cache.get(key); ok {
+			hit := pipeline.cloneInput(cached, nil)
+			pipeline.recordSince(start)
+			return hit, nil
+		}
+	}
+
+	workingSlice, err := pipeline.apply(input, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(options, Opt_Reset) {
+		if pipeline.cache == nil {
+			pipeline.cache = newResultCache[T](defaultCacheSize)
+		}
+		pipeline.cache.put(key, pipeline.cloneInput(workingSlice, nil))
+	}
+
+	return workingSlice, nil
+}
+
+// Bound the number of results ApplyCached() keeps, evicting the least recently used if it shrinks.
+func (pipeline *Pipeline[T]) SetCacheSize(n int) error {
+	if n < 1 {
+		return fmt.Errorf("SetCacheSize(%v): cache size must be at least 1: %w", n, ErrInvalidCount)
+	}
+
+	if pipeline.cache == nil {
+		pipeline.cache = newResultCache[T](n)
+		return nil
+	}
+
+	pipeline.cache.size = n
+	pipeline.cache.evict()
+
+	return nil
+}
+
+// Drop every result cached by ApplyCached(). The cache size is kept.
+func (pipeline *Pipeline[T]) ClearCache() {
+	if pipeline.cache == nil {
+		return
+	}
+
+	pipeline.cache = newResultCache[T](pipeline.cache.size)
+}
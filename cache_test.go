@@ -0,0 +1,59 @@
+package derp
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestApplyCached(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5}
+	var pipe Pipeline[int]
+
+	var calls atomic.Int64
+	pipe.Map(func(_, value int) int {
+		calls.Add(1)
+		return value * 2
+	})
+
+	keyer := func(in []int) string {
+		return fmt.Sprint(in)
+	}
+
+	pipe.SetCacheSize(1)
+
+	first, err := pipe.ApplyCached(numbers, keyer)
+	if err != nil {
+		t.Fatalf("TestApplyCached(); error from ApplyCached(): %v", err)
+	}
+
+	first[0] = 100 // must not leak into the cache
+
+	second, err := pipe.ApplyCached(numbers, keyer)
+	if err != nil {
+		t.Fatalf("TestApplyCached(); error from ApplyCached(): %v", err)
+	}
+
+	if expected := []int{2, 4, 6, 8, 10}; !slices.Equal(expected, second) {
+		t.Errorf("TestApplyCached(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, second)
+	}
+
+	if calls.Load() != int64(len(numbers)) {
+		t.Errorf("TestApplyCached(); expected cache hit.\nExpected: [%v] calls Got: [%v]\n", len(numbers), calls.Load())
+	}
+
+	pipe.ApplyCached([]int{1}, keyer) // evicts numbers at size 1
+	pipe.ApplyCached(numbers, keyer)
+
+	if calls.Load() != int64(2*len(numbers)+1) {
+		t.Errorf("TestApplyCached(); expected eviction.\nExpected: [%v] calls Got: [%v]\n", 2*len(numbers)+1, calls.Load())
+	}
+
+	pipe.ClearCache()
+	pipe.ApplyCached(numbers, keyer)
+
+	if calls.Load() != int64(3*len(numbers)+1) {
+		t.Errorf("TestApplyCached(); expected miss after ClearCache().\nExpected: [%v] calls Got: [%v]\n", 3*len(numbers)+1, calls.Load())
+	}
+}
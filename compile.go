@@ -0,0 +1,50 @@
+package derp
+
+// Frozen pipelines that are safe to Apply() from many goroutines.
+
+import (
+	"slices"
+	"sync"
+)
+
+// An immutable snapshot of a pipeline, made by Compile(). Its only method is Apply(), which is safe to call
+// from any number of goroutines at once. The zero value runs no orders.
+type CompiledPipeline[T any] struct {
+	pipeline *Pipeline[T]
+}
+
+// Freeze the pipeline's current orders into a CompiledPipeline. Reduce is moved to the end once, here, rather
+// than on every Apply(). Orders added to pipeline afterwards don't reach the compiled copy.
+//
+// The functions themselves are shared, so they must be safe to call concurrently. The same goes for
+// Collect() and ForeachCollect() targets, which every Apply() writes to. WithStats() keeps
+// recording into pipeline's stats.
+func (pipeline *Pipeline[T]) Compile() CompiledPipeline[T] {
+	frozen := *pipeline
+
+	// the instruction slices are only ever appended to, but orders is rearranged in place by hoistReduce()
+	// and WithStageWorkers(), so it gets its own backing array
+	frozen.orders = slices.Clone(pipeline.orders)
+	frozen.hoistReduce(nil)
+
+	frozen.bufPool = &sync.Pool{} // made up front so Opt_ClonePool doesn't create it mid-Apply
+	frozen.cache = nil
+	frozen.rejects, frozen.chunks, frozen.stop, frozen.dst, frozen.pool = nil, nil, nil, nil, nil
+
+	return CompiledPipeline[T]{pipeline: &frozen}
+}
+
+// Same as Pipeline.Apply(), except Opt_Reset is ignored and Opt_NoReduceReorder has no effect, since
+// Reduce was already placed by Compile().
+func (compiled CompiledPipeline[T]) Apply(input []T, options ...Option) ([]T, error) {
+	if compiled.pipeline == nil {
+		var empty Pipeline[T]
+		compiled.pipeline = &empty
+	}
+
+	options = append(slices.DeleteFunc(slices.Clone(options), func(opt Option) bool {
+		return opt == Opt_Reset || opt == Opt_NoReduceReorder
+	}), Opt_NoReduceReorder)
+
+	return compiled.pipeline.Apply(input, options...)
+}
@@ -0,0 +1,47 @@
+package derp
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	var pipeline Pipeline[int]
+	pipeline.SetSerialThreshold(0)
+	if err := pipeline.Reduce(func(acc, value int) int { return acc + value }); err != nil {
+		t.Fatal(err)
+	}
+	pipeline.Filter(func(value int) bool { return value%2 == 0 })
+	pipeline.Map(func(index int, value int) int { return value * 3 })
+
+	compiled := pipeline.Compile()
+
+	// later changes to the source don't leak into the compiled copy
+	pipeline.Map(func(index int, value int) int { return 0 })
+
+	input := Range(10_000)
+	expected := []int{74_985_000}
+
+	var wg sync.WaitGroup
+	for idx := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			options := []Option{Opt_Reset}
+			if idx%2 == 0 {
+				options = append(options, Opt_ClonePool)
+			}
+
+			gotten, err := compiled.Apply(input, options...)
+			if err != nil || !slices.Equal(gotten, expected) {
+				t.Errorf("TestCompile(); value mismatch.\nExpected: [%v] Got: [%v %v]\n", expected, gotten, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if gotten, err := (CompiledPipeline[int]{}).Apply([]int{1, 2}); err != nil || !slices.Equal(gotten, []int{1, 2}) {
+		t.Errorf("TestCompile(); zero value mismatch.\nExpected: [[1 2]] Got: [%v %v]\n", gotten, err)
+	}
+}
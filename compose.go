@@ -0,0 +1,272 @@
+package derp
+
+// Functions that run one or more pipelines and combine their results.
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Run pipelines a and b concurrently over the same input and return both results.
+//
+// Each branch works on its own deep clone of input, so neither can observe the other's mutations.
+// Under Opt_InPlace or Opt_MapInPlace, branch a works on input directly and branch b still gets a clone.
+// Options apply to both branches; Opt_Reset clears both pipelines afterwards. Each pipeline's WithStats()
+// counts the call once.
+func Tee[T any](input []T, a, b *Pipeline[T], options ...Option) (resA []T, resB []T, err error) {
+	if len(input) < 1 {
+		return nil, nil, ErrEmptyInput
+	}
+
+	if err := checkOptions(options); err != nil {
+		return nil, nil, err
+	}
+
+	if err := a.checkOrders(options); err != nil {
+		return nil, nil, err
+	}
+
+	if err := b.checkOrders(options); err != nil {
+		return nil, nil, err
+	}
+
+	// only one branch may own input; b always gets a clone
+	optionsB := slices.DeleteFunc(slices.Clone(options), func(opt Option) bool {
+		return opt == Opt_InPlace || opt == Opt_MapInPlace
+	})
+
+	if err := a.checkClone(options); err != nil {
+		return nil, nil, err
+	}
+
+	if err := b.checkClone(optionsB); err != nil {
+		return nil, nil, err
+	}
+
+	a.hoistReduce(options)
+	b.hoistReduce(options)
+
+	start := time.Now()
+	workingA := a.cloneInput(input, options)
+	workingB := b.cloneInput(input, optionsB)
+
+	var wg sync.WaitGroup
+	var errA, errB error
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		resA, errA = a.run(workingA, options)
+	}()
+
+	go func() {
+		defer wg.Done()
+		resB, errB = b.run(workingB, optionsB)
+	}()
+
+	wg.Wait()
+
+	if err := errors.Join(errA, errB); err != nil {
+		return nil, nil, err
+	}
+
+	a.recordSince(start)
+	b.recordSince(start)
+
+	if slices.Contains(options, Opt_Reset) {
+		*a = Pipeline[T]{}
+		*b = Pipeline[T]{}
+	}
+
+	return resA, resB, nil
+}
+
+// Run pA over inA and pB over inB concurrently, then pair their results index by index with combine,
+// eg. to merge two independently cleaned sources into records. Both results must be the same length.
+// Options apply to both pipelines.
+func CombineApply[A, B, R any](inA []A, pA *Pipeline[A], inB []B, pB *Pipeline[B], combine func(A, B) R, options ...Option) ([]R, error) {
+	if combine == nil {
+		return nil, fmt.Errorf("CombineApply(): %w", ErrNilFunc)
+	}
+
+	var resA []A
+	var resB []B
+	var errA, errB error
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		resA, errA = pA.apply(inA, options)
+	}()
+
+	go func() {
+		defer wg.Done()
+		resB, errB = pB.apply(inB, options)
+	}()
+
+	wg.Wait()
+
+	if err := errors.Join(errA, errB); err != nil {
+		return nil, err
+	}
+
+	if len(resA) != len(resB) {
+		return nil, fmt.Errorf("CombineApply(): %v results from a, %v from b", len(resA), len(resB))
+	}
+
+	out := make([]R, len(resA))
+
+	parallelChunks(len(out), runtime.GOMAXPROCS(0), func(_, start, end int) {
+		for idx := start; idx < end; idx++ {
+			out[idx] = combine(resA[idx], resB[idx])
+		}
+	})
+
+	return out, nil
+}
+
+// Run the pipeline, then route each result into one of buckets slices by the index classify returns.
+// Order is preserved within each bucket. A bucket index outside [0, buckets) is an error.
+func Split[T any](in []T, pipeline *Pipeline[T], buckets int, classify func(T) int, options ...Option) ([][]T, error) {
+	if buckets < 1 {
+		return nil, fmt.Errorf("Split(%v): need at least one bucket: %w", buckets, ErrInvalidCount)
+	}
+
+	workingSlice, err := pipeline.apply(in, options)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]T, buckets)
+
+	for idx, val := range workingSlice {
+		bucket := classify(val)
+		if bucket < 0 || bucket >= buckets {
+			return nil, fmt.Errorf("Split(): element %v classified into bucket %v: %w [0, %v)", idx, bucket, ErrOutOfRange, buckets)
+		}
+
+		out[bucket] = append(out[bucket], val)
+	}
+
+	return out, nil
+}
+
+// Run the pipeline, bucket its results by the key route returns, then run each bucket through the branch
+// registered for that key, all branches concurrently. Order is preserved within each bucket.
+//
+// Results whose key has no branch are returned as they are, or dropped under Opt_DropUnrouted. Keys with no
+// results are left out. Branches work on the buckets directly, since those already belong to Route(), so each
+// *Pipeline may back only one key. Options apply to the base pipeline and every branch, minus the clone options.
+func Route[T any, K comparable](in []T, pipeline *Pipeline[T], route func(T) K, branches map[K]*Pipeline[T], options ...Option) (map[K][]T, error) {
+	if route == nil {
+		return nil, fmt.Errorf("Route(): %w", ErrNilFunc)
+	}
+
+	workingSlice, err := pipeline.apply(in, options)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make(map[K][]T)
+	for _, val := range workingSlice {
+		key := route(val)
+		buckets[key] = append(buckets[key], val)
+	}
+
+	cloneOpts := []Option{Opt_InPlace, Opt_Clone, Opt_DPC, Opt_MapInPlace, Opt_ClonePool}
+	branchOptions := append(slices.DeleteFunc(slices.Clone(options), func(opt Option) bool {
+		return slices.Contains(cloneOpts, opt)
+	}), Opt_InPlace)
+
+	out := make(map[K][]T, len(buckets))
+	errs := make([]error, 0, len(buckets))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for key, bucket := range buckets {
+		branch, ok := branches[key]
+		if !ok {
+			if !slices.Contains(options, Opt_DropUnrouted) {
+				mu.Lock()
+				out[key] = bucket
+				mu.Unlock()
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result, err := branch.apply(bucket, branchOptions)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Route(): branch %v: %w", key, err))
+				return
+			}
+			out[key] = result
+		}()
+	}
+
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Run the pipeline, then Join() the results with sep.
+// A free function because Go methods can't be specialised to Pipeline[string].
+func ApplyJoin(input []string, pipeline *Pipeline[string], sep string, options ...Option) (string, error) {
+	workingSlice, err := pipeline.apply(input, options)
+	if err != nil {
+		return "", err
+	}
+
+	return Join(workingSlice, sep), nil
+}
+
+// Run every order except Reduce, then fold the results into an A starting from initial.
+// A free function because Go methods can't add type parameters; use it to reduce into a map, struct,
+// strings.Builder, etc. The pipeline's own Reduce, if any, is left out. Opt_Reset still clears the pipeline.
+func ReduceTo[T, A any](in []T, pipeline *Pipeline[T], initial A, fn func(acc A, v T) A, options ...Option) (A, error) {
+	if fn == nil {
+		return initial, fmt.Errorf("ReduceTo(): %w", ErrNilFunc)
+	}
+
+	trimmed := *pipeline
+	trimmed.orders = slices.DeleteFunc(slices.Clone(pipeline.orders), func(ord order) bool {
+		return ord.method == "reduce"
+	})
+	trimmed.reduceInstruct = nil
+	trimmed.reduceIndexed = nil
+	trimmed.reduceWhile = nil
+
+	workingSlice, err := trimmed.apply(in, options)
+	if err != nil {
+		return initial, err
+	}
+
+	if slices.Contains(options, Opt_Reset) {
+		*pipeline = Pipeline[T]{}
+	}
+
+	acc := initial
+	for _, val := range workingSlice {
+		acc = fn(acc, val)
+	}
+
+	return acc, nil
+}
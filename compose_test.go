@@ -0,0 +1,256 @@
+package derp
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTee(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	var evens, doubled Pipeline[int]
+
+	evens.Filter(func(value int) bool {
+		return value%2 == 0
+	})
+
+	doubled.Map(func(_, value int) int {
+		return value * 2
+	})
+
+	resA, resB, err := Tee(numbers, &evens, &doubled)
+	if err != nil {
+		t.Fatalf("TestTee(); error from Tee(): %v", err)
+	}
+
+	if expected := []int{2, 4, 6, 8, 10}; !slices.Equal(expected, resA) {
+		t.Errorf("TestTee(); branch a value mismatch.\nExpected: [%v] Got: [%v]\n", expected, resA)
+	}
+
+	if expected := []int{2, 4, 6, 8, 10, 12, 14, 16, 18, 20}; !slices.Equal(expected, resB) {
+		t.Errorf("TestTee(); branch b value mismatch.\nExpected: [%v] Got: [%v]\n", expected, resB)
+	}
+
+	if !slices.Equal(numbers, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}) {
+		t.Errorf("TestTee(); underlying value type slice mutates")
+	}
+}
+
+func TestTeeMapInPlace(t *testing.T) {
+	input := Range(5000)
+	var a, b Pipeline[int]
+
+	a.Map(func(_, value int) int { return value + 1 })
+	b.Map(func(_, value int) int { return value * 2 })
+
+	// run under -race: only branch a may write input
+	resA, resB, err := Tee(input, &a, &b, Opt_MapInPlace)
+	if err != nil {
+		t.Fatalf("TestTeeMapInPlace(); error from Tee(): %v", err)
+	}
+
+	if resA[4999] != 5000 || resB[4999] != 9998 {
+		t.Errorf("TestTeeMapInPlace(); value mismatch.\nExpected: [5000 9998] Got: [%v %v]\n", resA[4999], resB[4999])
+	}
+
+	if &resA[0] != &input[0] || &resB[0] == &input[0] {
+		t.Errorf("TestTeeMapInPlace(); expected only branch a to share input's backing array")
+	}
+}
+
+func TestTeeIndependentClones(t *testing.T) {
+	type record struct {
+		tags []string
+	}
+
+	records := []record{{tags: []string{"x"}}}
+	var a, b Pipeline[record]
+
+	a.Map(func(_ int, value record) record {
+		value.tags[0] = "a"
+		return value
+	})
+
+	b.Map(func(_ int, value record) record {
+		value.tags[0] = "b"
+		return value
+	})
+
+	resA, resB, err := Tee(records, &a, &b)
+	if err != nil {
+		t.Fatalf("TestTeeIndependentClones(); error from Tee(): %v", err)
+	}
+
+	if resA[0].tags[0] != "a" || resB[0].tags[0] != "b" {
+		t.Errorf("TestTeeIndependentClones(); branches interfered.\nGot: [%v] and [%v]\n", resA, resB)
+	}
+
+	if records[0].tags[0] != "x" {
+		t.Errorf("TestTeeIndependentClones(); input mutated. Got: [%v]\n", records)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	var pipe Pipeline[int]
+
+	pipe.Map(func(_, value int) int {
+		return value * 10
+	})
+
+	gotten, err := Split(numbers, &pipe, 3, func(value int) int {
+		return (value / 10) % 3
+	})
+	if err != nil {
+		t.Fatalf("TestSplit(); error from Split(): %v", err)
+	}
+
+	expected := [][]int{{30, 60, 90}, {10, 40, 70, 100}, {20, 50, 80}}
+
+	if len(expected) != len(gotten) {
+		t.Fatalf("TestSplit(); bucket count mismatch.\nExpected: [%v] Got: [%v]\n", len(expected), len(gotten))
+	}
+
+	for idx, val := range expected {
+		if !slices.Equal(val, gotten[idx]) {
+			t.Errorf("TestSplit(); bucket %v mismatch.\nExpected: [%v] Got: [%v]\n", idx, val, gotten[idx])
+		}
+	}
+
+	if _, err := Split(numbers, &pipe, 2, func(value int) int { return 2 }); err == nil {
+		t.Errorf("TestSplit(); expected error for out of range bucket")
+	}
+}
+
+func TestCombineApply(t *testing.T) {
+	type record struct {
+		id   int
+		name string
+	}
+
+	var ids Pipeline[int]
+	ids.Filter(func(value int) bool { return value > 0 })
+	ids.Map(func(_, value int) int { return value + 1000 })
+
+	var names Pipeline[string]
+	names.Filter(func(value string) bool { return value != "" })
+	names.Map(func(_ int, value string) string { return strings.ToUpper(value) })
+
+	gotten, err := CombineApply([]int{1, -1, 2}, &ids, []string{"ada", "", "bob"}, &names, func(id int, name string) record {
+		return record{id, name}
+	})
+	if err != nil {
+		t.Fatalf("TestCombineApply(); error from CombineApply(): %v", err)
+	}
+
+	expected := []record{{1001, "ADA"}, {1002, "BOB"}}
+	if !slices.Equal(gotten, expected) {
+		t.Errorf("TestCombineApply(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if _, err := CombineApply([]int{1, 2}, &ids, []string{"ada"}, &names, func(int, string) record { return record{} }); err == nil {
+		t.Errorf("TestCombineApply(); expected an error for mismatched lengths")
+	}
+}
+
+func TestRoute(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	var pipe Pipeline[int]
+
+	pipe.Map(func(_, value int) int {
+		return value * 10
+	})
+
+	kind := func(value int) string {
+		switch (value / 10) % 3 {
+		case 0:
+			return "fizz"
+		case 1:
+			return "odd"
+		default:
+			return "unrouted"
+		}
+	}
+
+	var fizz, odd Pipeline[int]
+	fizz.Map(func(_, value int) int { return -value })
+	odd.Filter(func(value int) bool { return value > 50 })
+
+	branches := map[string]*Pipeline[int]{"fizz": &fizz, "odd": &odd}
+
+	gotten, err := Route(numbers, &pipe, kind, branches)
+	if err != nil {
+		t.Fatalf("TestRoute(); error from Route(): %v", err)
+	}
+
+	expected := map[string][]int{"fizz": {-30, -60, -90}, "odd": {70, 100}, "unrouted": {20, 50, 80}}
+	for key, val := range expected {
+		if !slices.Equal(val, gotten[key]) {
+			t.Errorf("TestRoute(); bucket %v mismatch.\nExpected: [%v] Got: [%v]\n", key, val, gotten[key])
+		}
+	}
+
+	gotten, err = Route(numbers, &pipe, kind, branches, Opt_DropUnrouted)
+	if _, ok := gotten["unrouted"]; ok || err != nil || len(gotten) != 2 {
+		t.Errorf("TestRoute(); Opt_DropUnrouted kept [%v], err: [%v]\n", gotten, err)
+	}
+
+	if _, err := Route(numbers, &pipe, nil, branches); !errors.Is(err, ErrNilFunc) {
+		t.Errorf("TestRoute(); expected ErrNilFunc. Got: [%v]\n", err)
+	}
+}
+
+func TestApplyJoin(t *testing.T) {
+	words := []string{"derp", "", "is", "", "reusable"}
+	var pipe Pipeline[string]
+
+	pipe.Filter(func(value string) bool {
+		return value != ""
+	})
+
+	gotten, err := ApplyJoin(words, &pipe, " ")
+	if err != nil {
+		t.Errorf("TestApplyJoin(); error from ApplyJoin(): %v", err)
+	}
+
+	if gotten != "derp is reusable" {
+		t.Errorf("TestApplyJoin(); value mismatch.\nExpected: [derp is reusable] Got: [%v]\n", gotten)
+	}
+}
+
+func TestReduceTo(t *testing.T) {
+	var pipe Pipeline[int]
+
+	pipe.Filter(func(value int) bool {
+		return value%2 == 0
+	})
+	pipe.Reduce(func(acc, value int) int {
+		return acc + value
+	})
+
+	var sb strings.Builder
+	gotten, err := ReduceTo([]int{1, 2, 3, 4, 5, 6}, &pipe, &sb, func(acc *strings.Builder, value int) *strings.Builder {
+		if acc.Len() > 0 {
+			acc.WriteString(",")
+		}
+		acc.WriteString(strconv.Itoa(value))
+		return acc
+	})
+	if err != nil {
+		t.Fatalf("TestReduceTo(); error from ReduceTo(): %v", err)
+	}
+
+	if gotten.String() != "2,4,6" {
+		t.Errorf("TestReduceTo(); value mismatch.\nExpected: [2,4,6] Got: [%v]\n", gotten.String())
+	}
+
+	if len(pipe.orders) != 2 {
+		t.Errorf("TestReduceTo(); pipeline orders should be untouched. Got: [%v]\n", len(pipe.orders))
+	}
+
+	if _, err := ReduceTo[int, int]([]int{1}, &pipe, 0, nil); !errors.Is(err, ErrNilFunc) {
+		t.Errorf("TestReduceTo(); expected ErrNilFunc. Got: [%v]\n", err)
+	}
+}
@@ -10,13 +10,18 @@ package derp
 */
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"math/rand/v2"
+	"reflect"
 	"runtime"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	clone "github.com/huandu/go-clone/generic"
 )
@@ -33,28 +38,156 @@ const (
 	Opt_Power50
 	Opt_Power75
 	Opt_Reset
+	Opt_Power100
+	Opt_RequireOrders
+	Opt_NoReduceReorder
+	Opt_CollectErrors
+	Opt_MapInPlace
+	Opt_ClonePool
+	Opt_Deterministic
+	Opt_PreserveOrder
+	Opt_UnorderedFast
+	Opt_DropUnrouted
+)
+
+func (opt Option) String() string {
+	switch opt {
+	case Opt_InPlace:
+		return "Opt_InPlace"
+	case Opt_Clone:
+		return "Opt_Clone"
+	case Opt_DPC:
+		return "Opt_DPC"
+	case Opt_CFE:
+		return "Opt_CFE"
+	case Opt_Power25:
+		return "Opt_Power25"
+	case Opt_Power50:
+		return "Opt_Power50"
+	case Opt_Power75:
+		return "Opt_Power75"
+	case Opt_Reset:
+		return "Opt_Reset"
+	case Opt_Power100:
+		return "Opt_Power100"
+	case Opt_RequireOrders:
+		return "Opt_RequireOrders"
+	case Opt_NoReduceReorder:
+		return "Opt_NoReduceReorder"
+	case Opt_CollectErrors:
+		return "Opt_CollectErrors"
+	case Opt_MapInPlace:
+		return "Opt_MapInPlace"
+	case Opt_ClonePool:
+		return "Opt_ClonePool"
+	case Opt_Deterministic:
+		return "Opt_Deterministic"
+	case Opt_PreserveOrder:
+		return "Opt_PreserveOrder"
+	case Opt_UnorderedFast:
+		return "Opt_UnorderedFast"
+	case Opt_DropUnrouted:
+		return "Opt_DropUnrouted"
+	default:
+		return "Option(" + strconv.Itoa(int(opt)) + ")"
+	}
+}
+
+// Sentinel errors, for matching with errors.Is().
+var (
+	ErrEmptyInput        = errors.New("empty input slice")
+	ErrMultipleCloneOpts = errors.New("cannot invoke multiple cloning options")
+	ErrMultiplePowerOpts = errors.New("cannot invoke multiple power throttling options")
+	ErrMultipleOrderOpts = errors.New("cannot invoke multiple ordering options")
+	ErrUnsupportedOption = errors.New("option not supported here")
+	ErrReduceAlreadySet  = errors.New("Reduce has already been set")
+	ErrInvalidCount      = errors.New("invalid count")
+	ErrNilFunc           = errors.New("nil function")
+	ErrNoOrders          = errors.New("no orders registered")
+	ErrOutOfRange        = errors.New("out of range")
+	ErrNotMapOnly        = errors.New("Opt_MapInPlace requires a pipeline of only Map orders")
+	ErrUnknownStage      = errors.New("unknown stage kind")
+	ErrStatefulOrder     = errors.New("order needs the whole input at once")
+	ErrStopped           = errors.New("stopped before finishing")
+	ErrStop              = errors.New("stop requested")
+	ErrPoolClosed        = errors.New("worker pool is nil or closed")
+	ErrUnclonable        = errors.New("element type holds a lock or atomic that must not be copied; set a clone hook with CloneWith() or use Opt_InPlace")
 )
 
 type order struct {
 	method   string
 	index    int
 	comments []string
+	workers  int // per-stage override from WithStageWorkers(); 0 means the global count
 }
 
-type Pipeline[T any] struct {
-	filterInstructs  []func(t T) bool
-	foreachInstructs []func(t T)
-	mapInstructs     []func(index int, t T) T
-	reduceInstruct   func(a T, v T) T
-	skipCounts       []int
-	takeCounts       []int
+type asyncForeach[T any] struct {
+	fn          func(t T)
+	concurrency int
+}
+
+type sample struct {
+	k    int
+	seed int64
+}
 
-	orders []order
+type Pipeline[T any] struct {
+	collectTargets      []*[]T
+	distinctLastKeys    []func(t T) string
+	filterInstructs     []func(t T) bool
+	foreachInstructs    []func(t T)
+	foreachAsyncs       []asyncForeach[T]
+	foreachIdxInstructs []func(index int, t T)
+	foreachSinks        []*[]T
+	mapInstructs        []func(index int, t T) T
+	reduceInstruct      func(a T, v T) T
+	reduceIndexed       func(a T, index int, v T) T
+	reduceWhile         func(a T, v T) (T, bool)
+	reduceRight         bool
+	reduceAssociative   bool
+	reduceHereInstructs []func(a T, v T) T
+	samples             []sample
+	shuffleSeeds        []int64
+	skipCounts          []int
+	sortInstructs       []func(a, b T) int
+	takeCounts          []int
+	tryMapInstructs     []func(index int, t T) (T, error)
+	tryForeachInstructs []func(t T) error
+
+	orders    []order
+	cache     *resultCache[T]
+	cloneFunc func(input []T) []T
+	seed      int64
+	seeded    bool
+	bufPool   *sync.Pool
+	stats     *applyStats
+	name      string
+	rejects   [][]T           // only set while ApplyWithRejects() runs
+	chunks    *[][]T          // only set while ApplyChunked() runs
+	dst       *[]T            // only set while ApplyInto() runs
+	stop      <-chan struct{} // only set while ApplyStop() runs
+	pool      *WorkerPool     // only set while ApplyPool() runs
+	batching  bool            // only set while ApplyBatched() runs, which times the call as a whole
+
+	serialThreshold    int
+	hasSerialThreshold bool
 }
 
 func (pipeline Pipeline[T]) String() string {
 	var out strings.Builder
 
+	if pipeline.name != "" {
+		fmt.Fprintf(&out, "Pipeline: %v\n", pipeline.name)
+	}
+
+	if pipeline.cloneFunc != nil {
+		out.WriteString("Clone: custom\n")
+	} else if reflect.TypeFor[T]().Kind() == reflect.String {
+		fmt.Fprintf(&out, "Clone: auto (shallow copy for %v)\n", reflect.TypeFor[[]T]())
+	} else {
+		fmt.Fprintf(&out, "Clone: auto (%v for %v)\n", Opt_Clone, reflect.TypeFor[[]T]())
+	}
+
 	for idx, val := range pipeline.orders {
 		var prettyComments strings.Builder
 
@@ -73,8 +206,121 @@ func (pipeline Pipeline[T]) String() string {
 	return out.String()
 }
 
-// Keep only the elements where in returns true. Optional comment strings.
+// Label the pipeline, eg. to tell plans apart in logs. Shown by String() and carried in Stats().
+func (pipeline *Pipeline[T]) WithName(name string) {
+	pipeline.name = name
+}
+
+// Replace the default clone with fn, eg. for types go-clone can't handle. fn must return a slice that
+// shares nothing mutable with input. An explicit clone option passed to Apply() still takes precedence.
+func (pipeline *Pipeline[T]) CloneWith(fn func(input []T) []T) {
+	pipeline.cloneFunc = fn
+}
+
+// Seed every randomized order (Sample, Shuffle) from seed, overriding their own seeds. Each order's
+// generator is derived from seed and the order's position, so one value pins down the whole pipeline:
+// the same pipeline, input and seed always yield the same output. Filter, Map and the other ordered
+// orders already produce the same output regardless of worker count.
+func (pipeline *Pipeline[T]) WithSeed(seed int64) {
+	pipeline.seed = seed
+	pipeline.seeded = true
+}
+
+// Working slices shorter than this run each stage serially unless changed with SetSerialThreshold().
+const defaultSerialThreshold = 1024
+
+// Run a stage on the calling goroutine, without spawning workers, whenever the working slice has fewer
+// than n elements; below a certain size the goroutine and WaitGroup overhead outweighs the parallel speedup.
+// Results are identical either way. Defaults to 1024; 0 always runs in parallel.
+func (pipeline *Pipeline[T]) SetSerialThreshold(n int) error {
+	if n < 0 {
+		return fmt.Errorf("SetSerialThreshold(%v): %w", n, ErrInvalidCount)
+	}
+
+	pipeline.serialThreshold = n
+	pipeline.hasSerialThreshold = true
+
+	return nil
+}
+
+// Run the order at orderIndex (0-based, in the order added) with n workers instead of the count derived from
+// the power options, eg. 1 for a cheap stage or more for an expensive one. The serial threshold and
+// Opt_Deterministic still apply. The setting travels with the order if Reduce is moved to the end.
+func (pipeline *Pipeline[T]) WithStageWorkers(orderIndex, n int) error {
+	if orderIndex < 0 || orderIndex >= len(pipeline.orders) {
+		return fmt.Errorf("WithStageWorkers(%v, %v): order %w [0, %v)", orderIndex, n, ErrOutOfRange, len(pipeline.orders))
+	}
+	if n < 1 {
+		return fmt.Errorf("WithStageWorkers(%v, %v): %w", orderIndex, n, ErrInvalidCount)
+	}
+
+	pipeline.orders[orderIndex].workers = n
+
+	return nil
+}
+
+// Workers for a stage over length elements; one below the serial threshold.
+func (pipeline *Pipeline[T]) stageWorkers(length, numWorkers int) int {
+	threshold := defaultSerialThreshold
+	if pipeline.hasSerialThreshold {
+		threshold = pipeline.serialThreshold
+	}
+
+	if length < threshold {
+		return 1
+	}
+
+	return numWorkers
+}
+
+// Generator for the randomized order at position pos, honoring WithSeed().
+func (pipeline *Pipeline[T]) rng(pos int, orderSeed int64) *rand.Rand {
+	if pipeline.seeded {
+		derived := mix64(uint64(pipeline.seed) ^ mix64(uint64(pos)))
+		return rand.New(rand.NewPCG(derived, derived))
+	}
+
+	return rand.New(rand.NewPCG(uint64(orderSeed), uint64(orderSeed)))
+}
+
+// Snapshot the working slice into *into at this point of the pipeline, then carry on. The snapshot is a deep
+// clone, made by the CloneWith() hook if set, so later orders can't change it. Good for asserting on
+// intermediate state in tests.
+// Optional comment strings. Panics if into is nil.
+func (pipeline *Pipeline[T]) Collect(into *[]T, comments ...string) {
+	if into == nil {
+		panic("derp: Collect() called with a nil pointer")
+	}
+
+	pipeline.collectTargets = append(pipeline.collectTargets, into)
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "collect",
+		index:    len(pipeline.collectTargets) - 1,
+		comments: comments,
+	})
+}
+
+// Drop duplicates by key, keeping the last element seen for each key, eg. the newest record per ID.
+// The survivors keep their relative order. Runs serially. Optional comment strings. Panics if key is nil.
+func (pipeline *Pipeline[T]) DistinctLast(key func(value T) string, comments ...string) {
+	if key == nil {
+		panic("derp: DistinctLast() called with a nil function")
+	}
+
+	pipeline.distinctLastKeys = append(pipeline.distinctLastKeys, key)
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "distinctLast",
+		index:    len(pipeline.distinctLastKeys) - 1,
+		comments: comments,
+	})
+}
+
+// Keep only the elements where in returns true. Optional comment strings. Panics if in is nil.
 func (pipeline *Pipeline[T]) Filter(in func(value T) bool, comments ...string) {
+	if in == nil {
+		panic("derp: Filter() called with a nil function")
+	}
+
 	pipeline.filterInstructs = append(pipeline.filterInstructs, in)
 	pipeline.orders = append(pipeline.orders, order{
 		method:   "filter",
@@ -83,9 +329,44 @@ func (pipeline *Pipeline[T]) Filter(in func(value T) bool, comments ...string) {
 	})
 }
 
+// Keep only the elements where pred returns false; the inverse of Filter. Optional comment strings.
+// Panics if pred is nil.
+func (pipeline *Pipeline[T]) FilterNot(pred func(value T) bool, comments ...string) {
+	if pred == nil {
+		panic("derp: FilterNot() called with a nil function")
+	}
+
+	pipeline.Filter(func(value T) bool {
+		return !pred(value)
+	}, comments...)
+}
+
+// Drop the elements isZero reports as empty, eg. after a Map that blanks out unwanted values. Runs as a
+// Filter. For comparable types, CompactZero() supplies isZero. Optional comment strings. Panics if isZero is nil.
+func (pipeline *Pipeline[T]) CompactFunc(isZero func(value T) bool, comments ...string) {
+	if isZero == nil {
+		panic("derp: CompactFunc() called with a nil function")
+	}
+
+	pipeline.FilterNot(isZero, comments...)
+}
+
+// Drop the elements equal to T's zero value, eg. "" or nil. See CompactFunc() for non-comparable types.
+func CompactZero[T comparable](pipeline *Pipeline[T], comments ...string) {
+	var zero T
+
+	pipeline.CompactFunc(func(value T) bool {
+		return value == zero
+	}, comments...)
+}
+
 // Perform logic using each element as an input. No changes to the underlying elements are made.
-// Optional comment strings.
+// Optional comment strings. Panics if in is nil.
 func (pipeline *Pipeline[T]) Foreach(in func(value T), comments ...string) {
+	if in == nil {
+		panic("derp: Foreach() called with a nil function")
+	}
+
 	pipeline.foreachInstructs = append(pipeline.foreachInstructs, in)
 	pipeline.orders = append(pipeline.orders, order{
 		method:   "foreach",
@@ -94,8 +375,83 @@ func (pipeline *Pipeline[T]) Foreach(in func(value T), comments ...string) {
 	})
 }
 
-// Transform each value with access to its index in the current slice.
+// Like Foreach, but with up to concurrency calls in flight at once no matter how many CPUs there are, eg.
+// 100 for I/O-bound work such as HTTP requests. Runs concurrently with or without Opt_CFE, so in must be safe
+// for concurrent use; call order is non-deterministic. Opt_Deterministic still runs it serially.
+// Optional comment strings. Panics if in is nil.
+func (pipeline *Pipeline[T]) ForeachAsync(in func(value T), concurrency int, comments ...string) error {
+	if in == nil {
+		panic("derp: ForeachAsync() called with a nil function")
+	}
+	if concurrency < 1 {
+		return fmt.Errorf("ForeachAsync(%v): No order submitted: %w", concurrency, ErrInvalidCount)
+	}
+
+	pipeline.foreachAsyncs = append(pipeline.foreachAsyncs, asyncForeach[T]{fn: in, concurrency: concurrency})
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "foreachAsync",
+		index:    len(pipeline.foreachAsyncs) - 1,
+		comments: comments,
+	})
+
+	return nil
+}
+
+// Like Foreach, with access to each element's index in the current slice. The index stays correct under
+// Opt_CFE. Optional comment strings. Panics if in is nil.
+func (pipeline *Pipeline[T]) ForeachIndexed(in func(index int, value T), comments ...string) {
+	if in == nil {
+		panic("derp: ForeachIndexed() called with a nil function")
+	}
+
+	pipeline.foreachIdxInstructs = append(pipeline.foreachIdxInstructs, in)
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "foreachIndexed",
+		index:    len(pipeline.foreachIdxInstructs) - 1,
+		comments: comments,
+	})
+}
+
+// Like Foreach, but in may fail; the first error aborts Apply() and is returned, wrapped with the element's
+// index. Serially nothing after the failing element is visited. Under Opt_CFE the other workers are told to
+// stop and bail out at their next element, and the error at the lowest index among those hit is returned.
+// Optional comment strings. Panics if in is nil.
+func (pipeline *Pipeline[T]) TryForeach(in func(value T) error, comments ...string) {
+	if in == nil {
+		panic("derp: TryForeach() called with a nil function")
+	}
+
+	pipeline.tryForeachInstructs = append(pipeline.tryForeachInstructs, in)
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "tryForeach",
+		index:    len(pipeline.tryForeachInstructs) - 1,
+		comments: comments,
+	})
+}
+
+// Append every element to *sink, eg. to gather results without writing a Foreach and a mutex for Opt_CFE.
+// The append happens in one step on the goroutine running Apply(), so it is safe under any option and *sink
+// always gets the elements in their current order. Elements are copied shallowly.
+// Optional comment strings. Panics if sink is nil.
+func (pipeline *Pipeline[T]) ForeachCollect(sink *[]T, comments ...string) {
+	if sink == nil {
+		panic("derp: ForeachCollect() called with a nil pointer")
+	}
+
+	pipeline.foreachSinks = append(pipeline.foreachSinks, sink)
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "foreachCollect",
+		index:    len(pipeline.foreachSinks) - 1,
+		comments: comments,
+	})
+}
+
+// Transform each value with access to its index in the current slice. Panics if in is nil.
 func (pipeline *Pipeline[T]) Map(in func(index int, value T) T, comments ...string) {
+	if in == nil {
+		panic("derp: Map() called with a nil function")
+	}
+
 	pipeline.mapInstructs = append(pipeline.mapInstructs, in)
 	pipeline.orders = append(pipeline.orders, order{
 		method:   "map",
@@ -104,18 +460,40 @@ func (pipeline *Pipeline[T]) Map(in func(index int, value T) T, comments ...stri
 	})
 }
 
+// Like Map, but in may fail. A real error aborts Apply() and is returned, wrapped with the element's index.
+// Returning ErrStop instead ends the whole pipeline gracefully: Apply() returns the items before the stopping
+// one with a nil error, and no later orders run. Items past the stop may already have been passed to in by
+// other workers; their results are discarded. Optional comment strings. Panics if in is nil.
+func (pipeline *Pipeline[T]) TryMap(in func(index int, value T) (T, error), comments ...string) {
+	if in == nil {
+		panic("derp: TryMap() called with a nil function")
+	}
+
+	pipeline.tryMapInstructs = append(pipeline.tryMapInstructs, in)
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "tryMap",
+		index:    len(pipeline.tryMapInstructs) - 1,
+		comments: comments,
+	})
+}
+
 // Reduce sets a terminal operation that aggregates all elements of the pipeline into a single value.
 //
 // The provided function `in` is called with an accumulator and each element of the slice,
 // in order. The result of each call becomes the new accumulator for the next element.
 //
 // Only one Reduce can be set per pipeline. It is automatically executed last
-// regardless of the order in which it was added.
+// regardless of the order in which it was added, unless Apply() is given Opt_NoReduceReorder.
 //
 // When Apply() is run, Apply()'s output will be a []T with a single element.
+// See ReduceHere() for a reduce that runs where it was added.
 func (pipeline *Pipeline[T]) Reduce(in func(acc T, value T) T, comments ...string) error {
-	if pipeline.reduceInstruct != nil {
-		return fmt.Errorf("Reduce has already been set")
+	if in == nil {
+		return fmt.Errorf("Reduce(): %w", ErrNilFunc)
+	}
+
+	if pipeline.hasReduce() {
+		return ErrReduceAlreadySet
 	}
 
 	pipeline.reduceInstruct = in
@@ -127,10 +505,149 @@ func (pipeline *Pipeline[T]) Reduce(in func(acc T, value T) T, comments ...strin
 	return nil
 }
 
-// Skip the first n items and yield the rest. Comment inferred.
+// Like Reduce, but folds from the last element toward the first: acc starts as the last element and each
+// call sees the next one to its left, eg. subtraction over [1, 2, 3] gives (3 - 2) - 1 = 0 where Reduce
+// gives (1 - 2) - 3 = -4. Shares Reduce's single slot, so only one of the two may be set per pipeline.
+func (pipeline *Pipeline[T]) ReduceRight(in func(acc T, value T) T, comments ...string) error {
+	if in == nil {
+		return fmt.Errorf("ReduceRight(): %w", ErrNilFunc)
+	}
+
+	if err := pipeline.Reduce(in, comments...); err != nil {
+		return err
+	}
+
+	pipeline.reduceRight = true
+
+	return nil
+}
+
+// Like Reduce, but in also gets each element's index in the working slice, eg. for a weighted sum.
+// acc starts as the zero value of T and every element is folded in, including the first, so a sum of
+// index * value over [1, 1, 1, 1] is 0 + 1 + 2 + 3 = 6. Serial, and shares Reduce's single slot.
+func (pipeline *Pipeline[T]) ReduceIndexed(in func(acc T, index int, value T) T, comments ...string) error {
+	if in == nil {
+		return fmt.Errorf("ReduceIndexed(): %w", ErrNilFunc)
+	}
+
+	if pipeline.hasReduce() {
+		return ErrReduceAlreadySet
+	}
+
+	pipeline.reduceIndexed = in
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "reduce",
+		comments: comments,
+	})
+
+	return nil
+}
+
+// Like Reduce, but in also reports whether to carry on; once it returns false, the accumulator it returned is
+// the result and the remaining elements are never visited, eg. a logical AND that stops at the first false.
+// Serial, and shares Reduce's single slot.
+func (pipeline *Pipeline[T]) ReduceWhile(in func(acc T, value T) (T, bool), comments ...string) error {
+	if in == nil {
+		return fmt.Errorf("ReduceWhile(): %w", ErrNilFunc)
+	}
+
+	if pipeline.hasReduce() {
+		return ErrReduceAlreadySet
+	}
+
+	pipeline.reduceWhile = in
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "reduce",
+		comments: comments,
+	})
+
+	return nil
+}
+
+// Whether Reduce's single slot is taken, by Reduce() or one of its variants.
+func (pipeline *Pipeline[T]) hasReduce() bool {
+	return pipeline.reduceInstruct != nil || pipeline.reduceIndexed != nil || pipeline.reduceWhile != nil
+}
+
+// ReduceHere collapses the working slice to a single element at the position it was added.
+//
+// Unlike Reduce, it is not moved to the end of the pipeline and any number of them may be registered,
+// so later orders (eg. a Map) see the one-element result. Reducing an empty slice leaves it empty.
+// Optional comment strings. Panics if in is nil.
+func (pipeline *Pipeline[T]) ReduceHere(in func(acc T, value T) T, comments ...string) {
+	if in == nil {
+		panic("derp: ReduceHere() called with a nil function")
+	}
+
+	pipeline.reduceHereInstructs = append(pipeline.reduceHereInstructs, in)
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "reduceHere",
+		index:    len(pipeline.reduceHereInstructs) - 1,
+		comments: comments,
+	})
+}
+
+// Keep a uniformly random subset of k items via reservoir sampling, so the working slice is walked once.
+// A fixed seed always produces the same sample for the same input. If k is at least the number of items,
+// everything is kept. Comment inferred.
+func (pipeline *Pipeline[T]) Sample(k int, seed int64) error {
+	if k < 1 {
+		return fmt.Errorf("Sample(%v): No order submitted: %w", k, ErrInvalidCount)
+	}
+
+	pipeline.samples = append(pipeline.samples, sample{k: k, seed: seed})
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "sample",
+		index:    len(pipeline.samples) - 1,
+		comments: []string{"sample(" + strconv.Itoa(k) + ", " + strconv.FormatInt(seed, 10) + ")"},
+	})
+
+	return nil
+}
+
+// Randomly permute the items with a Fisher–Yates shuffle. A fixed seed always produces the same permutation
+// for the same input. Runs serially. The error is always nil; it keeps the signature in line with Sample.
+// Comment inferred.
+func (pipeline *Pipeline[T]) Shuffle(seed int64) error {
+	pipeline.shuffleSeeds = append(pipeline.shuffleSeeds, seed)
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "shuffle",
+		index:    len(pipeline.shuffleSeeds) - 1,
+		comments: []string{"shuffle(" + strconv.FormatInt(seed, 10) + ")"},
+	})
+
+	return nil
+}
+
+// Stable-sort the items by cmps in turn, like SQL's ORDER BY a, b: each comparator only decides ties left
+// by the ones before it. Wrap a comparator with Desc() to reverse it. Large slices are sorted in parallel.
+// Optional comment strings. Panics if cmps is empty or holds a nil function.
+func (pipeline *Pipeline[T]) SortByKeys(cmps []func(a, b T) int, comments ...string) {
+	if len(cmps) == 0 || slices.ContainsFunc(cmps, func(cmp func(a, b T) int) bool { return cmp == nil }) {
+		panic("derp: SortByKeys() called with a nil or empty comparator list")
+	}
+
+	cmps = slices.Clone(cmps)
+
+	pipeline.sortInstructs = append(pipeline.sortInstructs, func(a, b T) int {
+		for _, cmp := range cmps {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "sort",
+		index:    len(pipeline.sortInstructs) - 1,
+		comments: comments,
+	})
+}
+
+// Skip the first n items and yield the rest. Skip(0) is a no-op. Comment inferred.
 func (pipeline *Pipeline[T]) Skip(n int) error {
-	if n < 1 {
-		return fmt.Errorf("Skip(%v): No order submitted", n)
+	if n < 0 {
+		return fmt.Errorf("Skip(%v): No order submitted: %w", n, ErrInvalidCount)
 	}
 
 	pipeline.skipCounts = append(pipeline.skipCounts, n)
@@ -143,75 +660,590 @@ func (pipeline *Pipeline[T]) Skip(n int) error {
 	return nil
 }
 
-// Yield only the first n items from the pipeline. Comment inferred.
-func (pipeline *Pipeline[T]) Take(n int) error {
-	if n < 1 {
-		return fmt.Errorf("Take(%v): No order submitted", n)
+// Yield only the first n items from the pipeline. Take(0) yields an empty slice. Comment inferred.
+//
+// Take cuts the working slice where it sits in the pipeline, so Take(5) then a Filter keeps the survivors
+// among the first 5 items, not the first 5 survivors. For those, add the Take after the Filter or use ApplyFirst().
+func (pipeline *Pipeline[T]) Take(n int) error {
+	if n < 0 {
+		return fmt.Errorf("Take(%v): No order submitted: %w", n, ErrInvalidCount)
+	}
+
+	pipeline.takeCounts = append(pipeline.takeCounts, n)
+	pipeline.orders = append(pipeline.orders, order{
+		method:   "take",
+		index:    len(pipeline.takeCounts) - 1,
+		comments: []string{"take(" + strconv.Itoa(n) + ")"},
+	})
+
+	return nil
+}
+
+// Check the pipeline is consistent before an expensive Apply(): every order points at an existing
+// instruction, no instruction function is nil, and at most one Reduce is registered.
+func (pipeline *Pipeline[T]) Validate() error {
+	reduces := 0
+
+	for pos, ord := range pipeline.orders {
+		var err error
+
+		switch ord.method {
+		case "collect":
+			err = checkInstruct(ord.index, len(pipeline.collectTargets), func() bool { return pipeline.collectTargets[ord.index] == nil })
+		case "distinctLast":
+			err = checkInstruct(ord.index, len(pipeline.distinctLastKeys), func() bool { return pipeline.distinctLastKeys[ord.index] == nil })
+		case "filter":
+			err = checkInstruct(ord.index, len(pipeline.filterInstructs), func() bool { return pipeline.filterInstructs[ord.index] == nil })
+		case "foreach":
+			err = checkInstruct(ord.index, len(pipeline.foreachInstructs), func() bool { return pipeline.foreachInstructs[ord.index] == nil })
+		case "foreachAsync":
+			err = checkInstruct(ord.index, len(pipeline.foreachAsyncs), func() bool { return pipeline.foreachAsyncs[ord.index].fn == nil })
+		case "foreachCollect":
+			err = checkInstruct(ord.index, len(pipeline.foreachSinks), func() bool { return pipeline.foreachSinks[ord.index] == nil })
+		case "foreachIndexed":
+			err = checkInstruct(ord.index, len(pipeline.foreachIdxInstructs), func() bool { return pipeline.foreachIdxInstructs[ord.index] == nil })
+		case "map":
+			err = checkInstruct(ord.index, len(pipeline.mapInstructs), func() bool { return pipeline.mapInstructs[ord.index] == nil })
+		case "reduce":
+			reduces++
+			if reduces > 1 {
+				err = ErrReduceAlreadySet
+			} else if !pipeline.hasReduce() {
+				err = ErrNilFunc
+			}
+		case "reduceHere":
+			err = checkInstruct(ord.index, len(pipeline.reduceHereInstructs), func() bool { return pipeline.reduceHereInstructs[ord.index] == nil })
+		case "sample":
+			err = checkInstruct(ord.index, len(pipeline.samples), nil)
+		case "shuffle":
+			err = checkInstruct(ord.index, len(pipeline.shuffleSeeds), nil)
+		case "skip":
+			err = checkInstruct(ord.index, len(pipeline.skipCounts), nil)
+		case "sort":
+			err = checkInstruct(ord.index, len(pipeline.sortInstructs), func() bool { return pipeline.sortInstructs[ord.index] == nil })
+		case "take":
+			err = checkInstruct(ord.index, len(pipeline.takeCounts), nil)
+		case "tryForeach":
+			err = checkInstruct(ord.index, len(pipeline.tryForeachInstructs), func() bool { return pipeline.tryForeachInstructs[ord.index] == nil })
+		case "tryMap":
+			err = checkInstruct(ord.index, len(pipeline.tryMapInstructs), func() bool { return pipeline.tryMapInstructs[ord.index] == nil })
+		default:
+			err = fmt.Errorf("unknown method %q", ord.method)
+		}
+
+		if err != nil {
+			return fmt.Errorf("Validate(): order %v (%v): %w", pos, ord.method, err)
+		}
+	}
+
+	return nil
+}
+
+// Index must be within [0, length); isNil, if given, is only called once it is.
+func checkInstruct(index, length int, isNil func() bool) error {
+	if index < 0 || index >= length {
+		return fmt.Errorf("index %v: %w [0, %v)", index, ErrOutOfRange, length)
+	}
+
+	if isNil != nil && isNil() {
+		return ErrNilFunc
+	}
+
+	return nil
+}
+
+// Interpret orders on data. Return new slice.
+//
+// Leading Filter, Skip and Take orders run on input before it is cloned, so only their survivors get cloned.
+// A Filter directly followed by Reduce folds its survivors straight away, cloning them chunk by chunk.
+//
+// Options:
+//   - Opt_Clone : deep-clone non pointer cycle data. Default. For pointer elements (eg. []*Foo) the pointees are
+//     cloned too, so mutating them in a Map never reaches the caller's data.
+//   - Opt_DPC : "(d)eep-clone (p)ointer (c)ycles"; eg. doubly-linked lists. Implements clone.Slowly().
+//   - Opt_InPlace : operate directly on the backing input array. Apply() returns nil and an error.
+//   - Opt_CFE : "(c)oncurrent (f)or(e)ach"; function eval order is non-deterministic. Use with caution.
+//   - Opt_Power25, Opt_Power50, Opt_Power75 : throttle cpu usage to 25, 50, or 75%. Default is 100%.
+//   - Opt_Power100 : explicit full power; same as passing no power option.
+//   - Opt_Reset : Clear pipeline instructions after Apply().
+//   - Opt_RequireOrders : return an error instead of the cloned input when no orders are registered.
+//   - Opt_NoReduceReorder : run Reduce where it was added instead of moving it to the end.
+//   - Opt_MapInPlace : skip cloning and write Map results straight back into input, which is also returned.
+//     Only for pipelines made entirely of Map orders; anything else is an error. Unsafe if the caller reads
+//     the original afterwards expecting it unchanged.
+//   - Opt_ClonePool : like Opt_Clone, but the working buffer comes from a pool kept on the pipeline and goes back
+//     to it after the run. The result is still a fresh copy the caller owns. Cuts GC churn when Apply() runs in
+//     a loop, especially when Filter or Take shrink the output.
+//   - Opt_Deterministic : run every stage, including Foreach under Opt_CFE, on a single worker in index order.
+//     A debugging aid for ruling out races in your own functions; pure pipelines give the same output either way.
+//   - Opt_PreserveOrder : Filter output keeps input order. Default.
+//   - Opt_UnorderedFast : let Filter compact survivors in place and backfill gaps from the end instead of keeping
+//     order, which skips the per-worker buffers. Only for callers that don't care about order.
+//   - Opt_DropUnrouted : for Route(); drop results whose key has no branch instead of passing them through.
+//     Ignored elsewhere.
+//   - Opt_CollectErrors : for DecodeJSON(); join every decode error instead of returning the first. Apply(),
+//     its variants and everything else that takes options reject it with ErrUnsupportedOption.
+func (pipeline *Pipeline[T]) Apply(input []T, options ...Option) ([]T, error) {
+	workingSlice, err := pipeline.apply(input, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(options, Opt_InPlace) {
+		return nil, nil
+	}
+
+	return workingSlice, nil
+}
+
+// Run the pipeline on a single value, eg. one incoming message. A Filter that rejects it gives an empty
+// result, not an error. One element is below the serial threshold, so no goroutines are started.
+// Since the wrapping slice belongs to ApplyOne(), the result is returned even under Opt_InPlace.
+func (pipeline *Pipeline[T]) ApplyOne(value T, options ...Option) ([]T, error) {
+	return pipeline.apply([]T{value}, options)
+}
+
+// Like Apply(), but write the result over dst's contents and return it, growing dst only if its capacity
+// falls short, eg. to reuse one buffer across calls in a hot loop. Input is normally cloned straight into
+// dst, so a Map-only pipeline allocates nothing for its result; with Opt_DPC, Opt_InPlace, Opt_MapInPlace
+// or a CloneWith() hook the result is copied into dst at the end instead. Returned even under Opt_InPlace.
+//
+// dst and input must not overlap unless Opt_InPlace or Opt_MapInPlace is given.
+func (pipeline *Pipeline[T]) ApplyInto(dst, input []T, options ...Option) ([]T, error) {
+	pipeline.dst = &dst
+	defer func() { pipeline.dst = nil }()
+
+	return pipeline.apply(input, options)
+}
+
+// Like Apply() with Opt_InPlace forced, but returns the result instead of nil.
+//
+// WARNING: input IS MUTATED. No cloning happens regardless of element kind: Map writes straight into input's
+// backing array and Filter compacts survivors into it, so input's contents are undefined afterwards.
+// Only use it on slices you own and will not read again; keep the returned slice instead.
+// Any clone option passed in is ignored.
+func (pipeline *Pipeline[T]) ApplyMut(input []T, options ...Option) ([]T, error) {
+	options = slices.DeleteFunc(slices.Clone(options), func(opt Option) bool {
+		return opt == Opt_Clone || opt == Opt_DPC || opt == Opt_MapInPlace || opt == Opt_ClonePool
+	})
+
+	return pipeline.apply(input, append(options, Opt_InPlace))
+}
+
+// Like Apply(), but also return what each Filter dropped. rejected lines up with the orders as they run
+// (after Reduce is moved to the end), holding the dropped elements at each Filter's position and nil
+// everywhere else. Handy for auditing why a row disappeared.
+func (pipeline *Pipeline[T]) ApplyWithRejects(input []T, options ...Option) (kept []T, rejected [][]T, err error) {
+	pipeline.hoistReduce(options)
+
+	rejected = make([][]T, len(pipeline.orders))
+	pipeline.rejects = rejected
+	defer func() { pipeline.rejects = nil }()
+
+	kept, err = pipeline.Apply(input, options...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return kept, rejected, nil
+}
+
+// Like Apply(), but return the result as the consecutive chunks the workers produced instead of one
+// flattened slice, so the caller can keep fanning out without re-chunking. Concatenating the chunks in
+// order gives Apply()'s output; Flatten() does exactly that. Some chunks may be empty.
+// When the last order is a Filter, no flattening happens at all.
+func (pipeline *Pipeline[T]) ApplyChunked(input []T, options ...Option) ([][]T, error) {
+	var chunks [][]T
+	pipeline.chunks = &chunks
+	defer func() { pipeline.chunks = nil }()
+
+	workingSlice, err := pipeline.apply(input, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if chunks != nil {
+		return chunks, nil
+	}
+
+	return splitChunks(workingSlice, pipeline.stageWorkers(len(workingSlice), workerCount(options))), nil
+}
+
+// Like Apply(), but run the whole order sequence over sequential batches of batchSize items and concatenate
+// the results, so peak memory is roughly one batch's working set instead of the whole input's.
+// Only orders that treat items independently are allowed: Reduce, ReduceHere, Sample, Shuffle, Skip, Take,
+// SortByKeys, Collect, DistinctLast and TryMap (whose ErrStop ends everything) are rejected with ErrStatefulOrder. Map and ForeachIndexed see batch-local indexes.
+func (pipeline *Pipeline[T]) ApplyBatched(input []T, batchSize int, options ...Option) ([]T, error) {
+	if batchSize < 1 {
+		return nil, fmt.Errorf("ApplyBatched(%v): %w", batchSize, ErrInvalidCount)
+	}
+
+	for _, ord := range pipeline.orders {
+		switch ord.method {
+		case "reduce", "reduceHere", "sample", "shuffle", "skip", "take", "sort", "collect", "tryMap", "distinctLast":
+			return nil, fmt.Errorf("ApplyBatched(): %v: %w", ord.method, ErrStatefulOrder)
+		}
+	}
+
+	if len(input) < 1 {
+		return nil, ErrEmptyInput
+	}
+
+	batchOptions := slices.DeleteFunc(slices.Clone(options), func(opt Option) bool {
+		return opt == Opt_Reset
+	})
+
+	start := time.Now()
+	pipeline.batching = true
+
+	var out []T
+	for lo := 0; lo < len(input); lo += batchSize {
+		workingSlice, err := pipeline.apply(input[lo:min(lo+batchSize, len(input))], batchOptions)
+		if err != nil {
+			pipeline.batching = false
+			return nil, err
+		}
+
+		out = append(out, workingSlice...)
+	}
+
+	pipeline.batching = false
+	pipeline.recordSince(start)
+
+	if slices.Contains(options, Opt_Reset) {
+		*pipeline = Pipeline[T]{}
+	}
+
+	if slices.Contains(options, Opt_InPlace) {
+		return nil, nil
+	}
+
+	return out, nil
+}
+
+// Items a worker processes between looks at ApplyStop()'s channel.
+const stopCheckInterval = 1024
+
+// Like Apply(), but give up with ErrStopped once stop is closed. The channel is checked between
+// orders and every so often inside the Filter, Map and Foreach loops; every worker has returned by the time
+// ApplyStop() does. A lighter alternative to a context for eg. CLI tools reacting to SIGINT.
+func (pipeline *Pipeline[T]) ApplyStop(input []T, stop <-chan struct{}, options ...Option) ([]T, error) {
+	pipeline.stop = stop
+	defer func() { pipeline.stop = nil }()
+
+	return pipeline.Apply(input, options...)
+}
+
+// Whether ApplyStop()'s channel has fired. Always false outside ApplyStop().
+func (pipeline *Pipeline[T]) stopped() bool {
+	if pipeline.stop == nil {
+		return false
+	}
+
+	select {
+	case <-pipeline.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Shared body of Apply and friends. Unlike Apply, it returns the working slice under Opt_InPlace too.
+func (pipeline *Pipeline[T]) apply(input []T, options []Option) ([]T, error) {
+	start := time.Now()
+
+	if err := pipeline.checkApply(input, options); err != nil {
+		return nil, err
+	}
+
+	// leading read-only orders run on input itself, so only their survivors get cloned
+	prefix := pipeline.readOnlyPrefix(options)
+
+	survivors, err := pipeline.runRange(input, options, 0, prefix, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer []T
+	into := pipeline.dst != nil && pipeline.clonesInto(options)
+	if into {
+		buffer = cloneInto(*pipeline.dst, survivors)
+	} else {
+		buffer = pipeline.cloneInput(survivors, options)
+	}
+
+	workingSlice, err := pipeline.runRange(buffer, options, prefix, len(pipeline.orders), false)
+
+	switch {
+	case pipeline.dst != nil:
+		base := *pipeline.dst
+		if into {
+			base = buffer // may have outgrown dst
+		}
+		workingSlice = append(base[:0], workingSlice...) // a no-op move when the result already sits at the front
+	case slices.Contains(options, Opt_ClonePool):
+		workingSlice = slices.Clone(workingSlice)
+		if pipeline.chunks != nil {
+			// a final Opt_UnorderedFast filter hands out chunks of the buffer itself
+			for idx, chunk := range *pipeline.chunks {
+				(*pipeline.chunks)[idx] = slices.Clone(chunk)
+			}
+		}
+		pipeline.releaseBuffer(buffer)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if pipeline.stopped() {
+		return nil, ErrStopped
+	}
+
+	pipeline.recordSince(start)
+
+	if slices.Contains(options, Opt_Reset) {
+		*pipeline = Pipeline[T]{}
+	}
+
+	return workingSlice, nil
+}
+
+// Everything Apply() verifies before touching input; also moves Reduce to the end.
+func (pipeline *Pipeline[T]) checkApply(input []T, options []Option) error {
+	if len(input) < 1 {
+		return ErrEmptyInput
+	}
+
+	pipeline.hoistReduce(options)
+
+	if err := checkOptions(options); err != nil {
+		return err
+	}
+
+	if err := pipeline.checkOrders(options); err != nil {
+		return err
+	}
+
+	return pipeline.checkClone(options)
+}
+
+// Reduce should be the last instruction, unless Opt_NoReduceReorder says otherwise.
+func (pipeline *Pipeline[T]) hoistReduce(options []Option) {
+	if slices.Contains(options, Opt_NoReduceReorder) {
+		return
+	}
+
+	if !pipeline.hasReduce() || pipeline.orders[len(pipeline.orders)-1].method == "reduce" {
+		return
+	}
+
+	for idx, ord := range pipeline.orders {
+		if ord.method == "reduce" {
+			pipeline.orders = append(pipeline.orders[:idx], pipeline.orders[idx+1:]...) // remove it where it is
+			pipeline.orders = append(pipeline.orders, ord)                              // put it on the end
+			break
+		}
+	}
+}
+
+// Ensure only one or less each clone opt, power opt and ordering opt, and no Opt_CollectErrors, which only
+// DecodeJSON() understands and takes out before checking.
+func checkOptions(options []Option) error {
+	if hasMultipleOpts(options, Opt_InPlace, Opt_Clone, Opt_DPC, Opt_MapInPlace, Opt_ClonePool) {
+		return ErrMultipleCloneOpts
+	}
+	if hasMultipleOpts(options, Opt_Power25, Opt_Power50, Opt_Power75, Opt_Power100) {
+		return ErrMultiplePowerOpts
+	}
+	if hasMultipleOpts(options, Opt_PreserveOrder, Opt_UnorderedFast) {
+		return ErrMultipleOrderOpts
+	}
+	if slices.Contains(options, Opt_CollectErrors) {
+		return fmt.Errorf("%v: %w", Opt_CollectErrors, ErrUnsupportedOption)
+	}
+
+	return nil
+}
+
+// Under Opt_RequireOrders, an empty pipeline is an error.
+func (pipeline *Pipeline[T]) checkOrders(options []Option) error {
+	if slices.Contains(options, Opt_RequireOrders) && len(pipeline.orders) == 0 {
+		return ErrNoOrders
+	}
+
+	if slices.Contains(options, Opt_MapInPlace) {
+		for _, ord := range pipeline.orders {
+			if ord.method != "map" {
+				return fmt.Errorf("found a %v order: %w", ord.method, ErrNotMapOnly)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Refuse to deep-clone element types that hold a sync or sync/atomic value, since copying one is a bug.
+// Only reflection-based clones are checked; Opt_InPlace, Opt_MapInPlace, string elements and a CloneWith()
+// hook without an explicit clone option all pass.
+func (pipeline *Pipeline[T]) checkClone(options []Option) error {
+	explicit := slices.Contains(options, Opt_Clone) || slices.Contains(options, Opt_DPC) || slices.Contains(options, Opt_ClonePool)
+
+	switch {
+	case slices.Contains(options, Opt_InPlace), slices.Contains(options, Opt_MapInPlace):
+		return nil
+	case !explicit && (pipeline.cloneFunc != nil || reflect.TypeFor[T]().Kind() == reflect.String):
+		return nil
+	}
+
+	if typ := reflect.TypeFor[T](); holdsSyncValue(typ, map[reflect.Type]bool{}) {
+		return fmt.Errorf("%v: %w", typ, ErrUnclonable)
+	}
+
+	return nil
+}
+
+// Whether typ contains, directly or through anything go-clone would follow, a value from sync or sync/atomic.
+func holdsSyncValue(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[typ] {
+		return false
 	}
+	seen[typ] = true
 
-	pipeline.takeCounts = append(pipeline.takeCounts, n)
-	pipeline.orders = append(pipeline.orders, order{
-		method:   "take",
-		index:    len(pipeline.takeCounts) - 1,
-		comments: []string{"take(" + strconv.Itoa(n) + ")"},
-	})
+	if pkg := typ.PkgPath(); typ.Name() != "" && (pkg == "sync" || pkg == "sync/atomic") {
+		return true
+	}
 
-	return nil
+	switch typ.Kind() {
+	case reflect.Array, reflect.Slice, reflect.Pointer:
+		return holdsSyncValue(typ.Elem(), seen)
+	case reflect.Map:
+		return holdsSyncValue(typ.Key(), seen) || holdsSyncValue(typ.Elem(), seen)
+	case reflect.Struct:
+		for idx := range typ.NumField() {
+			if holdsSyncValue(typ.Field(idx).Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
 }
 
-// Interpret orders on data. Return new slice.
-//
-// Options:
-//   - Opt_Clone : deep-clone non pointer cycle data. Default.
-//   - Opt_DPC : "(d)eep-clone (p)ointer (c)ycles"; eg. doubly-linked lists. Implements clone.Slowly().
-//   - Opt_InPlace : operate directly on the backing input array. Apply() returns nil and an error.
-//   - Opt_CFE : "(c)oncurrent (f)or(e)ach"; function eval order is non-deterministic. Use with caution.
-//   - Opt_Power25, Opt_Power50, Opt_Power75 : throttle cpu usage to 25, 50, or 75%. Default is 100%.
-//   - Opt_Reset : Clear pipeline instructions after Apply().
-func (pipeline *Pipeline[T]) Apply(input []T, options ...Option) ([]T, error) {
-	if len(input) < 1 {
-		var zero []T
-		return zero, fmt.Errorf("empty input slice")
+// Number of leading orders that only read and narrow the input (Filter, Skip, Take) and so can run before the
+// clone. Zero when nothing is cloned anyway, or when ApplyChunked() or ApplyWithRejects() would get the
+// prefix's uncloned elements.
+func (pipeline *Pipeline[T]) readOnlyPrefix(options []Option) int {
+	if slices.Contains(options, Opt_InPlace) || slices.Contains(options, Opt_MapInPlace) || pipeline.chunks != nil || pipeline.rejects != nil {
+		return 0
 	}
 
-	// Reduce should be the last instruction
-	if pipeline.reduceInstruct != nil && pipeline.orders[len(pipeline.orders)-1].method != "reduce" {
-		for idx, ord := range pipeline.orders {
-			if ord.method == "reduce" {
-				pipeline.orders = append(pipeline.orders[:idx], pipeline.orders[idx+1:]...) // remove it where it is
-				pipeline.orders = append(pipeline.orders, ord)                              // put it on the end
-				break
+	for pos, ord := range pipeline.orders {
+		switch ord.method {
+		case "filter":
+			// a filter right before the reduce is fused with it and clones only what it folds, see foldChunks()
+			if next := pos + 1; next < len(pipeline.orders) && pipeline.orders[next].method == "reduce" {
+				return next + 1
 			}
+		case "skip", "take":
+		default:
+			return pos
 		}
 	}
 
-	// Ensure only one or less each clone opt and power opt
-	if hasMultipleOpts(options, Opt_InPlace, Opt_Clone, Opt_DPC) {
-		return nil, fmt.Errorf("cannot invoke multiple cloning options")
+	return len(pipeline.orders)
+}
+
+// Produce the working slice according to the clone option. Without one, use the CloneWith() hook if set,
+// a plain copy for string elements, otherwise Opt_Clone.
+func (pipeline *Pipeline[T]) cloneInput(input []T, options []Option) []T {
+	switch {
+	case slices.Contains(options, Opt_InPlace), slices.Contains(options, Opt_MapInPlace):
+		return input
+	case slices.Contains(options, Opt_DPC):
+		return clone.Slowly(input)
+	case slices.Contains(options, Opt_Clone):
+		return clone.Clone(input)
+	case slices.Contains(options, Opt_ClonePool):
+		return pipeline.pooledClone(input)
+	case pipeline.cloneFunc != nil:
+		return pipeline.cloneFunc(input)
+	case reflect.TypeFor[T]().Kind() == reflect.String:
+		return slices.Clone(input) // strings are immutable; copying the headers is a full clone
+	default:
+		return clone.Clone(input)
+	}
+}
+
+// Deep-clone input into a buffer from the pipeline's pool. Elements that hold no pointers are copied
+// directly; the rest are cloned one by one so their pointees are never shared with the caller.
+func (pipeline *Pipeline[T]) pooledClone(input []T) []T {
+	if pipeline.bufPool == nil {
+		pipeline.bufPool = &sync.Pool{}
 	}
-	if hasMultipleOpts(options, Opt_Power25, Opt_Power50, Opt_Power75) {
-		return nil, fmt.Errorf("cannot invoke multiple power throttling options")
+
+	buffer, _ := pipeline.bufPool.Get().([]T)
+
+	return cloneInto(buffer, input)
+}
+
+// Deep-clone input over dst's contents, growing it only when its capacity falls short.
+func cloneInto[T any](dst, input []T) []T {
+	dst = slices.Grow(dst[:0], len(input))[:len(input)]
+
+	if needsDeepClone(reflect.TypeFor[T]()) {
+		for idx, val := range input {
+			dst[idx] = clone.Clone(val)
+		}
+	} else {
+		copy(dst, input)
 	}
 
-	//inputType := reflect.TypeOf(input[0])
-	hasExplicitCloneOption := slices.Contains(options, Opt_DPC) || slices.Contains(options, Opt_InPlace) || slices.Contains(options, Opt_Clone)
+	return dst
+}
 
-	// default to Clone
-	if !hasExplicitCloneOption {
-		options = append(options, Opt_Clone)
+// Whether the clone options leave cloning to go-clone, so ApplyInto() can clone straight into dst.
+func (pipeline *Pipeline[T]) clonesInto(options []Option) bool {
+	switch {
+	case slices.Contains(options, Opt_InPlace), slices.Contains(options, Opt_MapInPlace), slices.Contains(options, Opt_DPC):
+		return false
+	case slices.Contains(options, Opt_Clone), slices.Contains(options, Opt_ClonePool):
+		return true
+	default:
+		return pipeline.cloneFunc == nil
 	}
+}
 
-	var workingSlice []T
+// Hand a pooled buffer back, zeroed so it doesn't keep old elements alive.
+func (pipeline *Pipeline[T]) releaseBuffer(buffer []T) {
+	buffer = buffer[:cap(buffer)]
+	clear(buffer)
+	pipeline.bufPool.Put(buffer[:0]) //lint:ignore SA6002 the slice header allocation is small next to the buffer
+}
 
-	for _, opt := range options {
-		switch opt {
-		case Opt_InPlace:
-			workingSlice = input
-		case Opt_Clone:
-			workingSlice = clone.Clone(input)
-		case Opt_DPC:
-			workingSlice = clone.Slowly(input)
+// Whether values of typ reach memory a plain copy would share. Strings count as values since they're immutable.
+func needsDeepClone(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Array:
+		return needsDeepClone(typ.Elem())
+	case reflect.Struct:
+		for idx := range typ.NumField() {
+			if needsDeepClone(typ.Field(idx).Type) {
+				return true
+			}
 		}
+		return false
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return true
+	default:
+		return false
+	}
+}
+
+// Number of workers after power throttling. Always 1 under Opt_Deterministic.
+func workerCount(options []Option) int {
+	if slices.Contains(options, Opt_Deterministic) {
+		return 1
 	}
 
 	throttleMult := 1.0
@@ -223,51 +1255,122 @@ func (pipeline *Pipeline[T]) Apply(input []T, options ...Option) ([]T, error) {
 			throttleMult = 0.5
 		case Opt_Power75:
 			throttleMult = 0.75
+		case Opt_Power100:
+			throttleMult = 1.0
 		}
 	}
 
 	//log.Printf("Running at %v%% power", throttleMult*100)
-	numWorkers := int(math.Ceil(float64(runtime.GOMAXPROCS(0)) * throttleMult))
+	return max(int(math.Ceil(float64(runtime.GOMAXPROCS(0))*throttleMult)), 1)
+}
+
+// Fulfill every order against workingSlice and return what is left of it.
+func (pipeline *Pipeline[T]) run(workingSlice []T, options []Option) ([]T, error) {
+	return pipeline.runRange(workingSlice, options, 0, len(pipeline.orders), false)
+}
+
+// Fulfill orders [from, to). With readOnly set, workingSlice belongs to the caller and is never written to.
+func (pipeline *Pipeline[T]) runRange(workingSlice []T, options []Option, from, to int, readOnly bool) ([]T, error) {
+	numWorkers := workerCount(options)
+	deterministic := slices.Contains(options, Opt_Deterministic)
+	fused := false // the previous filter already did this order's reduce
+
+	for pos := from; pos < to; pos++ {
+		order := pipeline.orders[pos]
 
-	// init chunksize
-	chunkSize := (len(workingSlice) + numWorkers - 1) / numWorkers
+		if fused {
+			fused = false
+			continue
+		}
+
+		if pipeline.stopped() {
+			return workingSlice, nil
+		}
+
+		workers := numWorkers
+		if order.workers > 0 && !deterministic {
+			workers = order.workers
+		}
+		workers = pipeline.stageWorkers(len(workingSlice), workers)
 
-	for _, order := range pipeline.orders {
 		switch order.method {
-		case "filter":
-			workOrder := pipeline.filterInstructs[order.index]
-			results := make([][]T, numWorkers)
+		case "collect":
+			*pipeline.collectTargets[order.index] = pipeline.cloneInput(workingSlice, nil)
 
-			var wg sync.WaitGroup
-			wg.Add(numWorkers)
+		case "distinctLast":
+			key := pipeline.distinctLastKeys[order.index]
+			keys := make([]string, len(workingSlice))
+			last := make(map[string]int, len(workingSlice))
 
-			for idx := range numWorkers {
-				start := idx * chunkSize
+			for idx, val := range workingSlice {
+				keys[idx] = key(val)
+				last[keys[idx]] = idx
+			}
 
-				if start >= len(workingSlice) {
-					wg.Done()
-					continue
+			kept := workingSlice[:0]
+			for idx, val := range workingSlice {
+				if last[keys[idx]] == idx {
+					kept = append(kept, val)
 				}
+			}
 
-				// If the end marker runs longer than the slice, you've reached the end.
-				end := min(start+chunkSize, len(workingSlice))
+			workingSlice = kept
 
-				chunk := workingSlice[start:end]
+		case "filter":
+			workOrder := pipeline.filterInstructs[order.index]
 
-				go func(idx int, chunk []T) {
-					defer wg.Done()
+			if slices.Contains(options, Opt_UnorderedFast) && !readOnly {
+				workingSlice = pipeline.filterUnordered(workingSlice, workOrder, workers, pos)
+				continue
+			}
 
-					out := make([]T, 0, len(chunk))
-					for _, v := range chunk {
-						if workOrder(v) {
-							out = append(out, v)
-						}
+			results := make([][]T, workers)
+			dropped := make([][]T, workers)
+			collect := pipeline.rejects != nil
+
+			pipeline.parallelChunks(len(workingSlice), workers, func(worker, start, end int) {
+				chunk := workingSlice[start:end]
+
+				out := make([]T, 0, min(len(chunk), filterProbe))
+				for i, v := range chunk {
+					if i%stopCheckInterval == 0 && pipeline.stopped() {
+						return
 					}
-					results[idx] = out
-				}(idx, chunk)
+					if i == filterProbe {
+						out = slices.Grow(out, filterReserve(len(out), len(chunk)-i))
+					}
+					if workOrder(v) {
+						out = append(out, v)
+					} else if collect {
+						dropped[worker] = append(dropped[worker], v)
+					}
+				}
+				results[worker] = out
+			})
+
+			if collect {
+				pipeline.rejects[pos] = slices.Concat(dropped...)
 			}
 
-			wg.Wait()
+			// a final filter hands its per-worker results to ApplyChunked() as they are
+			if pipeline.chunks != nil && pos == len(pipeline.orders)-1 {
+				*pipeline.chunks = slices.DeleteFunc(results, func(r []T) bool { return r == nil })
+				workingSlice = nil
+				continue
+			}
+
+			// a reduce straight after folds the per-worker results as they are; no need to flatten first
+			if next := pos + 1; next < to && pipeline.orders[next].method == "reduce" {
+				if readOnly {
+					results = pipeline.cloneChunks(results, options)
+				}
+				workingSlice = pipeline.foldChunks(results)
+				if len(workingSlice) == 0 {
+					return workingSlice, nil // same early exit as the reduce itself
+				}
+				fused = true
+				continue
+			}
 
 			// Flatten
 			newlength := 0
@@ -277,7 +1380,7 @@ func (pipeline *Pipeline[T]) Apply(input []T, options ...Option) ([]T, error) {
 
 			// reuse buffers
 			var tempSlice []T
-			if cap(workingSlice) >= newlength {
+			if cap(workingSlice) >= newlength && !readOnly {
 				tempSlice = workingSlice[:0]
 			} else {
 				tempSlice = make([]T, 0, newlength)
@@ -293,64 +1396,159 @@ func (pipeline *Pipeline[T]) Apply(input []T, options ...Option) ([]T, error) {
 			workOrder := pipeline.foreachInstructs[order.index]
 
 			if len(options) > 0 && slices.Contains(options, Opt_CFE) {
-				var wg sync.WaitGroup
-				wg.Add(numWorkers)
-
-				for idx := range numWorkers {
-					start := idx * chunkSize
-
-					if start >= len(workingSlice) {
-						wg.Done()
-						continue
+				pipeline.parallelChunks(len(workingSlice), workers, func(_, start, end int) {
+					for i, v := range workingSlice[start:end] {
+						if i%stopCheckInterval == 0 && pipeline.stopped() {
+							return
+						}
+						workOrder(v)
+					}
+				})
+			} else {
+				for idx, val := range workingSlice {
+					if idx%stopCheckInterval == 0 && pipeline.stopped() {
+						break
 					}
+					workOrder(val)
+				}
+			}
 
-					end := min(start+chunkSize, len(workingSlice))
+		case "foreachAsync":
+			workOrder := pipeline.foreachAsyncs[order.index]
 
-					chunk := workingSlice[start:end]
+			concurrency := workOrder.concurrency
+			if deterministic {
+				concurrency = 1
+			}
+			concurrency = min(concurrency, len(workingSlice))
 
-					go func(chunk []T) {
-						defer wg.Done()
+			// a fixed pool pulling the next index, rather than a goroutine per element
+			var next atomic.Int64
+			var wg sync.WaitGroup
+			wg.Add(concurrency)
 
-						for _, v := range chunk {
-							workOrder(v)
+			for range concurrency {
+				go func() {
+					defer wg.Done()
+					for idx := int(next.Add(1) - 1); idx < len(workingSlice); idx = int(next.Add(1) - 1) {
+						if pipeline.stopped() {
+							return
 						}
-					}(chunk)
-				}
+						workOrder.fn(workingSlice[idx])
+					}
+				}()
+			}
 
-				wg.Wait()
+			wg.Wait()
+
+		case "foreachCollect":
+			sink := pipeline.foreachSinks[order.index]
+			*sink = append(*sink, workingSlice...)
 
+		case "foreachIndexed":
+			workOrder := pipeline.foreachIdxInstructs[order.index]
+
+			if len(options) > 0 && slices.Contains(options, Opt_CFE) {
+				pipeline.parallelChunks(len(workingSlice), workers, func(_, start, end int) {
+					for i, v := range workingSlice[start:end] {
+						if i%stopCheckInterval == 0 && pipeline.stopped() {
+							return
+						}
+						workOrder(start+i, v)
+					}
+				})
 			} else {
-				for _, val := range workingSlice {
-					workOrder(val)
+				for idx, val := range workingSlice {
+					if idx%stopCheckInterval == 0 && pipeline.stopped() {
+						break
+					}
+					workOrder(idx, val)
 				}
 			}
 
 		case "map":
 			workOrder := pipeline.mapInstructs[order.index]
 
-			var wg sync.WaitGroup
-			wg.Add(numWorkers)
+			pipeline.parallelChunks(len(workingSlice), workers, func(_, start, end int) {
+				c := workingSlice[start:end]
+				for i := range c {
+					if i%stopCheckInterval == 0 && pipeline.stopped() {
+						return
+					}
+					c[i] = workOrder(start+i, c[i])
+				}
+			})
 
-			for w := range numWorkers {
-				start := w * chunkSize
+		case "tryForeach":
+			workOrder := pipeline.tryForeachInstructs[order.index]
 
-				if start >= len(workingSlice) {
-					wg.Done()
-					continue
+			if !slices.Contains(options, Opt_CFE) {
+				for idx, val := range workingSlice {
+					if err := workOrder(val); err != nil {
+						return nil, fmt.Errorf("TryForeach(): element %v: %w", idx, err)
+					}
 				}
+				break
+			}
 
-				end := min(start+chunkSize, len(workingSlice))
+			var cancelled atomic.Bool
+			failedAt := make([]int, workers)
+			failures := make([]error, workers)
 
-				chunk := workingSlice[start:end]
+			pipeline.parallelChunks(len(workingSlice), workers, func(worker, start, end int) {
+				for idx := start; idx < end; idx++ {
+					if cancelled.Load() {
+						return
+					}
 
-				go func(c []T, start int) {
-					defer wg.Done()
-					for i := range c {
-						c[i] = workOrder(start+i, c[i])
+					if err := workOrder(workingSlice[idx]); err != nil {
+						failedAt[worker], failures[worker] = idx, err
+						cancelled.Store(true)
+						return
 					}
-				}(chunk, start)
+				}
+			})
+
+			for worker, err := range failures {
+				if err != nil {
+					return nil, fmt.Errorf("TryForeach(): element %v: %w", failedAt[worker], err)
+				}
+			}
+
+		case "tryMap":
+			workOrder := pipeline.tryMapInstructs[order.index]
+			failedAt := make([]int, workers)
+			failures := make([]error, workers)
+			for worker := range failedAt {
+				failedAt[worker] = -1
+			}
+
+			pipeline.parallelChunks(len(workingSlice), workers, func(worker, start, end int) {
+				for idx := start; idx < end; idx++ {
+					if (idx-start)%stopCheckInterval == 0 && pipeline.stopped() {
+						return
+					}
+
+					val, err := workOrder(idx, workingSlice[idx])
+					if err != nil {
+						failedAt[worker], failures[worker] = idx, err
+						return
+					}
+					workingSlice[idx] = val
+				}
+			})
+
+			// chunks are in worker order, so the first failing worker holds the earliest failure
+			for worker, idx := range failedAt {
+				if idx < 0 {
+					continue
+				}
+
+				if errors.Is(failures[worker], ErrStop) {
+					return workingSlice[:idx], nil
+				}
+				return nil, fmt.Errorf("TryMap(): element %v: %w", idx, failures[worker])
 			}
-			wg.Wait()
 
 		case "reduce":
 			workOrder := pipeline.reduceInstruct
@@ -359,6 +1557,62 @@ func (pipeline *Pipeline[T]) Apply(input []T, options ...Option) ([]T, error) {
 				return []T{}, nil
 			}
 
+			if pipeline.reduceIndexed != nil {
+				var acc T
+				for idx, v := range workingSlice {
+					acc = pipeline.reduceIndexed(acc, idx, v)
+				}
+
+				workingSlice = []T{acc}
+				break
+			}
+
+			if pipeline.reduceWhile != nil {
+				acc := workingSlice[0]
+				for _, v := range workingSlice[1:] {
+					var more bool
+					if acc, more = pipeline.reduceWhile(acc, v); !more {
+						break
+					}
+				}
+
+				workingSlice = []T{acc}
+				break
+			}
+
+			if pipeline.reduceAssociative {
+				partials := make([]T, workers)
+				filled := make([]bool, workers) // trailing workers get no chunk when the slice is short
+
+				pipeline.parallelChunks(len(workingSlice), workers, func(worker, start, end int) {
+					acc := workingSlice[start]
+					for _, v := range workingSlice[start+1 : end] {
+						acc = workOrder(acc, v)
+					}
+					partials[worker], filled[worker] = acc, true
+				})
+
+				acc := partials[0]
+				for idx, v := range partials[1:] {
+					if filled[idx+1] {
+						acc = workOrder(acc, v)
+					}
+				}
+
+				workingSlice = []T{acc}
+				break
+			}
+
+			if pipeline.reduceRight {
+				acc := workingSlice[len(workingSlice)-1]
+				for idx := len(workingSlice) - 2; idx >= 0; idx-- {
+					acc = workOrder(acc, workingSlice[idx])
+				}
+
+				workingSlice = []T{acc}
+				break
+			}
+
 			acc := workingSlice[0]
 			for _, v := range workingSlice[1:] {
 				acc = workOrder(acc, v)
@@ -366,6 +1620,46 @@ func (pipeline *Pipeline[T]) Apply(input []T, options ...Option) ([]T, error) {
 
 			workingSlice = []T{acc}
 
+		case "reduceHere":
+			workOrder := pipeline.reduceHereInstructs[order.index]
+
+			if len(workingSlice) == 0 {
+				break
+			}
+
+			acc := workingSlice[0]
+			for _, v := range workingSlice[1:] {
+				acc = workOrder(acc, v)
+			}
+
+			workingSlice = []T{acc}
+
+		case "sample":
+			workOrder := pipeline.samples[order.index]
+
+			if workOrder.k >= len(workingSlice) {
+				break
+			}
+
+			rng := pipeline.rng(pos, workOrder.seed)
+			reservoir := make([]T, workOrder.k)
+			copy(reservoir, workingSlice[:workOrder.k])
+
+			for idx := workOrder.k; idx < len(workingSlice); idx++ {
+				if j := rng.IntN(idx + 1); j < workOrder.k {
+					reservoir[j] = workingSlice[idx]
+				}
+			}
+
+			workingSlice = reservoir
+
+		case "shuffle":
+			rng := pipeline.rng(pos, pipeline.shuffleSeeds[order.index])
+
+			rng.Shuffle(len(workingSlice), func(i, j int) {
+				workingSlice[i], workingSlice[j] = workingSlice[j], workingSlice[i]
+			})
+
 		case "skip":
 			skipUntilIndex := pipeline.skipCounts[order.index]
 
@@ -375,6 +1669,13 @@ func (pipeline *Pipeline[T]) Apply(input []T, options ...Option) ([]T, error) {
 				workingSlice = workingSlice[skipUntilIndex:]
 			}
 
+		case "sort":
+			if workers == 1 {
+				slices.SortStableFunc(workingSlice, pipeline.sortInstructs[order.index])
+			} else {
+				parallelSort(workingSlice, pipeline.sortInstructs[order.index], slices.SortStableFunc[[]T], workers)
+			}
+
 		case "take":
 			takeUntilIndex := pipeline.takeCounts[order.index]
 
@@ -382,22 +1683,189 @@ func (pipeline *Pipeline[T]) Apply(input []T, options ...Option) ([]T, error) {
 				workingSlice = workingSlice[:takeUntilIndex]
 			}
 		}
+	}
+
+	return workingSlice, nil
+}
+
+// Filter for Opt_UnorderedFast. Each worker compacts its survivors to the front of its own chunk, then the holes
+// left below the final length are filled with survivors from above it, so at most one move per survivor.
+func (pipeline *Pipeline[T]) filterUnordered(workingSlice []T, workOrder func(T) bool, workers, pos int) []T {
+	bounds := make([][2]int, workers)
+	kept := make([]int, workers)
+	dropped := make([][]T, workers)
+	collect := pipeline.rejects != nil
+
+	pipeline.parallelChunks(len(workingSlice), workers, func(worker, start, end int) {
+		next := start
+		for idx := start; idx < end; idx++ {
+			if (idx-start)%stopCheckInterval == 0 && pipeline.stopped() {
+				return
+			}
+			if workOrder(workingSlice[idx]) {
+				workingSlice[next] = workingSlice[idx]
+				next++
+			} else if collect {
+				dropped[worker] = append(dropped[worker], workingSlice[idx])
+			}
+		}
+		bounds[worker], kept[worker] = [2]int{start, end}, next-start
+	})
 
-		// redistribute work evenly among workers after every order
-		//old := chunkSize
-		chunkSize = (len(workingSlice) + numWorkers - 1) / numWorkers
-		//log.Printf("Redistributing work:\n\tOld chunksize: %v\n\tNew chunksize: %v", old, chunkSize)
+	if collect {
+		pipeline.rejects[pos] = slices.Concat(dropped...)
 	}
 
-	if slices.Contains(options, Opt_Reset) {
-		*pipeline = Pipeline[T]{}
+	if pipeline.chunks != nil && pos == len(pipeline.orders)-1 {
+		for worker, bound := range bounds {
+			if bound[1] > bound[0] {
+				*pipeline.chunks = append(*pipeline.chunks, workingSlice[bound[0]:bound[0]+kept[worker]])
+			}
+		}
+		return nil
 	}
 
-	if slices.Contains(options, Opt_InPlace) {
-		return nil, nil
+	total := 0
+	for _, n := range kept {
+		total += n
 	}
 
-	return workingSlice, nil
+	var holes, extras [][2]int
+	for worker, bound := range bounds {
+		survivorsEnd := bound[0] + kept[worker]
+		if lo, hi := survivorsEnd, min(bound[1], total); lo < hi {
+			holes = append(holes, [2]int{lo, hi})
+		}
+		if lo, hi := max(bound[0], total), survivorsEnd; lo < hi {
+			extras = append(extras, [2]int{lo, hi})
+		}
+	}
+
+	// there are exactly as many holes below total as survivors above it
+	for len(holes) > 0 {
+		n := copy(workingSlice[holes[0][0]:holes[0][1]], workingSlice[extras[0][0]:extras[0][1]])
+		holes[0][0] += n
+		extras[0][0] += n
+
+		if holes[0][0] == holes[0][1] {
+			holes = holes[1:]
+		}
+		if extras[0][0] == extras[0][1] {
+			extras = extras[1:]
+		}
+	}
+
+	return workingSlice[:total]
+}
+
+// Elements a Filter worker checks before sizing its output from the survivor ratio so far.
+const filterProbe = 256
+
+// Room to reserve for the remaining elements of a chunk once kept of the first filterProbe have survived:
+// the ratio so far plus an eighth for noise. A dense filter then grows its output once instead of
+// doubling, and a sparse one never allocates for survivors it won't have.
+func filterReserve(kept, remaining int) int {
+	return min(remaining, remaining*(kept+kept/8+1)/filterProbe)
+}
+
+// Clone a read-only filter's per-worker results before a fused reduce gets to them. Plain values are
+// already copies in the workers' own buffers; anything else gets the usual clone, minus the pool.
+func (pipeline *Pipeline[T]) cloneChunks(results [][]T, options []Option) [][]T {
+	if pipeline.cloneFunc == nil && !needsDeepClone(reflect.TypeFor[T]()) && !slices.Contains(options, Opt_DPC) {
+		return results
+	}
+
+	cloneOptions := slices.Clone(options)
+	for idx, opt := range cloneOptions {
+		if opt == Opt_ClonePool {
+			cloneOptions[idx] = Opt_Clone // the chunks are folded and dropped; nothing to give back
+		}
+	}
+
+	for worker, r := range results {
+		if len(r) > 0 {
+			results[worker] = pipeline.cloneInput(r, cloneOptions)
+		}
+	}
+
+	return results
+}
+
+// Reduce across a filter's per-worker results in order, as if they had been flattened first.
+func (pipeline *Pipeline[T]) foldChunks(results [][]T) []T {
+	if pipeline.reduceIndexed != nil {
+		var acc T
+		idx := 0
+		for _, r := range results {
+			for _, v := range r {
+				acc = pipeline.reduceIndexed(acc, idx, v)
+				idx++
+			}
+		}
+
+		if idx == 0 {
+			return []T{}
+		}
+		return []T{acc}
+	}
+
+	if pipeline.reduceWhile != nil {
+		var acc T
+		started := false
+
+	chunks:
+		for _, r := range results {
+			for _, v := range r {
+				if !started {
+					acc, started = v, true
+					continue
+				}
+
+				var more bool
+				if acc, more = pipeline.reduceWhile(acc, v); !more {
+					break chunks
+				}
+			}
+		}
+
+		if !started {
+			return []T{}
+		}
+		return []T{acc}
+	}
+
+	workOrder := pipeline.reduceInstruct
+
+	var acc T
+	started := false
+
+	fold := func(v T) {
+		if started {
+			acc = workOrder(acc, v)
+		} else {
+			acc, started = v, true
+		}
+	}
+
+	if pipeline.reduceRight {
+		for _, r := range slices.Backward(results) {
+			for _, v := range slices.Backward(r) {
+				fold(v)
+			}
+		}
+	} else {
+		for _, r := range results {
+			for _, v := range r {
+				fold(v)
+			}
+		}
+	}
+
+	if !started {
+		return []T{}
+	}
+
+	return []T{acc}
 }
 
 func hasMultipleOpts(in []Option, targets ...Option) bool {
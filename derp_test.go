@@ -1,13 +1,18 @@
 package derp
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"reflect"
+	"runtime"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	clone "github.com/huandu/go-clone/generic"
 )
@@ -267,6 +272,76 @@ func TestForeachMut(t *testing.T) {
 	}
 }
 
+func TestFilterFunc(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	gotten := Filter(numbers, func(value int) bool {
+		return value%2 == 0 // return evens
+	})
+
+	expected := []int{2, 4, 6, 8, 10}
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestFilterFunc(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if gotten := Filter([]int{}, func(value int) bool { return true }); gotten == nil || len(gotten) != 0 {
+		t.Errorf("TestFilterFunc(); expected an empty slice for empty input. Got: [%v]\n", gotten)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("TestFilterFunc(); expected a panic under Opt_MapInPlace")
+		}
+	}()
+	Filter(numbers, func(value int) bool { return true }, Opt_MapInPlace)
+}
+
+func TestMapFunc(t *testing.T) {
+	numbers := []*int{new(int), new(int), new(int)}
+	for idx, val := range numbers {
+		*val = idx + 1
+	}
+
+	gotten := Map(numbers, func(_ int, value *int) *int {
+		*value *= *value // square the numbers
+		return value
+	})
+
+	for idx, val := range []int{1, 4, 9} {
+		if *gotten[idx] != val {
+			t.Errorf("TestMapFunc(); value mismatch at %v.\nExpected: [%v] Got: [%v]\n", idx, val, *gotten[idx])
+		}
+		if *numbers[idx] != idx+1 {
+			t.Errorf("TestMapFunc(); input pointee %v mutated. Got: [%v]\n", idx, *numbers[idx])
+		}
+	}
+
+	expectPanic := func(reason string, run func()) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("TestMapFunc(); expected a panic for %v", reason)
+			}
+		}()
+		run()
+	}
+
+	type guarded struct {
+		sync.Mutex
+		Value int
+	}
+
+	expectPanic("conflicting options", func() {
+		Map(numbers, func(_ int, value *int) *int { return value }, Opt_Clone, Opt_DPC)
+	})
+	expectPanic("an unclonable type", func() {
+		Map(make([]*guarded, 2), func(_ int, value *guarded) *guarded { return value })
+	})
+
+	if gotten := Map(make([]*guarded, 2), func(_ int, value *guarded) *guarded { return value }, Opt_InPlace); len(gotten) != 2 {
+		t.Errorf("TestMapFunc(); Opt_InPlace should not clone. Got: [%v]\n", gotten)
+	}
+}
+
 func TestMap(t *testing.T) {
 	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
 	var pipe Pipeline[int]
@@ -436,3 +511,1916 @@ func TestTake(t *testing.T) {
 		}
 	}
 }
+
+func TestTakeSkipZero(t *testing.T) {
+	numbers := []int{1, 2, 3}
+
+	var skipPipe Pipeline[int]
+	if err := skipPipe.Skip(0); err != nil {
+		t.Fatalf("TestTakeSkipZero(); error from Skip(0): %v", err)
+	}
+
+	gotten, err := skipPipe.Apply(numbers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(gotten, numbers) {
+		t.Errorf("TestTakeSkipZero(); Skip(0) value mismatch.\nExpected: [%v] Got: [%v]\n", numbers, gotten)
+	}
+
+	var takePipe Pipeline[int]
+	if err := takePipe.Take(0); err != nil {
+		t.Fatalf("TestTakeSkipZero(); error from Take(0): %v", err)
+	}
+
+	gotten, err = takePipe.Apply(numbers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotten == nil || len(gotten) != 0 {
+		t.Errorf("TestTakeSkipZero(); Take(0) expected an empty slice. Got: [%v]\n", gotten)
+	}
+
+	if err := takePipe.Take(-1); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("TestTakeSkipZero(); expected ErrInvalidCount for Take(-1). Got: [%v]\n", err)
+	}
+}
+
+func TestSample(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	var pipe Pipeline[int]
+
+	if err := pipe.Sample(3, 42); err != nil {
+		t.Fatalf("TestSample(); error from Sample(): %v", err)
+	}
+
+	first, err := pipe.Apply(numbers)
+	if err != nil {
+		t.Errorf("TestSample(); error from Apply(): %v", err)
+	}
+
+	second, err := pipe.Apply(numbers)
+	if err != nil {
+		t.Errorf("TestSample(); error from Apply(): %v", err)
+	}
+
+	if len(first) != 3 {
+		t.Errorf("TestSample(); length mismatch.\nExpected: [3] Got: [%v]\n", len(first))
+	}
+
+	if !slices.Equal(first, second) {
+		t.Errorf("TestSample(); same seed produced different samples.\nFirst: [%v] Second: [%v]\n", first, second)
+	}
+
+	for _, val := range first {
+		if !slices.Contains(numbers, val) {
+			t.Errorf("TestSample(); sampled value [%v] not in input", val)
+		}
+	}
+
+	var wholePipe Pipeline[int]
+	wholePipe.Sample(20, 42)
+
+	whole, _ := wholePipe.Apply(numbers)
+	if !slices.Equal(whole, numbers) {
+		t.Errorf("TestSample(); k >= len should keep everything.\nExpected: [%v] Got: [%v]\n", numbers, whole)
+	}
+
+	if err := pipe.Sample(0, 42); err == nil {
+		t.Errorf("TestSample(); expected error for k < 1")
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	var pipe Pipeline[int]
+
+	pipe.Shuffle(7)
+
+	expected := []int{3, 8, 9, 5, 6, 7, 2, 4, 1, 10} // golden permutation for seed 7
+	gotten, err := pipe.Apply(numbers)
+	if err != nil {
+		t.Errorf("TestShuffle(); error from Apply(): %v", err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestShuffle(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if !slices.Equal(numbers, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}) {
+		t.Errorf("TestShuffle(); underlying value type slice mutates")
+	}
+}
+
+func TestDeepClonePointerElements(t *testing.T) {
+	type foo struct {
+		X int
+	}
+
+	foos := []*foo{{X: 1}, {X: 2}}
+	var pipe Pipeline[*foo]
+
+	pipe.Map(func(_ int, value *foo) *foo {
+		value.X *= 10
+		return value
+	})
+
+	out, err := pipe.Apply(foos)
+	if err != nil {
+		t.Fatalf("TestDeepClonePointerElements(); error from Apply(): %v", err)
+	}
+
+	if out[0].X != 10 || out[1].X != 20 {
+		t.Errorf("TestDeepClonePointerElements(); mutation error, no change.\nExpected: [10 20] Got: [%v %v]\n", out[0].X, out[1].X)
+	}
+
+	if foos[0].X != 1 || foos[1].X != 2 {
+		t.Errorf("TestDeepClonePointerElements(); original pointees mutated.\nExpected: [1 2] Got: [%v %v]\n", foos[0].X, foos[1].X)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	var pipeline Pipeline[string]
+	pipeline.Map(func(index int, value string) string {
+		if strings.HasPrefix(value, "#") {
+			return ""
+		}
+		return strings.TrimSpace(value)
+	})
+	CompactZero(&pipeline)
+
+	out, err := pipeline.Apply([]string{" a ", "# comment", "b", "", "#", "c "})
+	if err != nil || !slices.Equal(out, []string{"a", "b", "c"}) {
+		t.Errorf("TestCompact(); value mismatch.\nExpected: [[a b c]] Got: [%v %v]\n", out, err)
+	}
+
+	var slicePipe Pipeline[[]int]
+	slicePipe.CompactFunc(func(value []int) bool { return len(value) == 0 })
+
+	nested, err := slicePipe.Apply([][]int{{1}, nil, {}, {2, 3}})
+	if err != nil || len(nested) != 2 || nested[1][1] != 3 {
+		t.Errorf("TestCompact(); CompactFunc() mismatch.\nExpected: [[[1] [2 3]]] Got: [%v %v]\n", nested, err)
+	}
+}
+
+func TestApplyOne(t *testing.T) {
+	var pipeline Pipeline[int]
+	pipeline.Map(func(index int, value int) int { return value * value })
+
+	out, err := pipeline.ApplyOne(7)
+	if err != nil || !slices.Equal(out, []int{49}) {
+		t.Errorf("TestApplyOne(); value mismatch.\nExpected: [[49]] Got: [%v %v]\n", out, err)
+	}
+
+	out, err = pipeline.ApplyOne(7, Opt_InPlace)
+	if err != nil || !slices.Equal(out, []int{49}) {
+		t.Errorf("TestApplyOne(); Opt_InPlace mismatch.\nExpected: [[49]] Got: [%v %v]\n", out, err)
+	}
+
+	pipeline.Filter(func(value int) bool { return value > 100 })
+
+	out, err = pipeline.ApplyOne(7)
+	if err != nil || len(out) != 0 {
+		t.Errorf("TestApplyOne(); rejected value.\nExpected: [[] <nil>] Got: [%v %v]\n", out, err)
+	}
+}
+
+func TestApplyMut(t *testing.T) {
+	type person struct {
+		name string
+		tags []string
+	}
+
+	people := []person{{name: "a", tags: []string{"x"}}, {name: "b", tags: []string{"y"}}}
+	var pipe Pipeline[person]
+
+	pipe.Map(func(_ int, value person) person {
+		value.tags[0] = "CHANGED"
+		return value
+	})
+
+	pipe.Filter(func(value person) bool {
+		return value.name == "b"
+	})
+
+	out, err := pipe.ApplyMut(people, Opt_Clone)
+	if err != nil {
+		t.Fatalf("TestApplyMut(); error from ApplyMut(): %v", err)
+	}
+
+	if len(out) != 1 || out[0].name != "b" {
+		t.Errorf("TestApplyMut(); value mismatch.\nExpected: [b] Got: [%v]\n", out)
+	}
+
+	if &out[0] != &people[0] {
+		t.Errorf("TestApplyMut(); expected result to share input's backing array")
+	}
+
+	if people[0].tags[0] != "CHANGED" {
+		t.Errorf("TestApplyMut(); expected input to be mutated")
+	}
+}
+
+func TestPower100(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	var pipe Pipeline[int]
+
+	pipe.Map(func(_, value int) int {
+		return value * 2
+	})
+
+	full, err := pipe.Apply(numbers, Opt_Power100)
+	if err != nil {
+		t.Errorf("TestPower100(); error from Apply(): %v", err)
+	}
+
+	implicit, _ := pipe.Apply(numbers)
+	if !slices.Equal(full, implicit) {
+		t.Errorf("TestPower100(); value mismatch.\nExpected: [%v] Got: [%v]\n", implicit, full)
+	}
+
+	if _, err := pipe.Apply(numbers, Opt_Power50, Opt_Power100); err == nil {
+		t.Errorf("TestPower100(); expected error for multiple power options")
+	}
+}
+
+func TestNilInstructions(t *testing.T) {
+	expectPanic := func(name string, register func()) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("TestNilInstructions(); expected %v() to panic on nil function", name)
+			}
+		}()
+		register()
+	}
+
+	var pipe Pipeline[int]
+
+	expectPanic("Filter", func() { pipe.Filter(nil) })
+	expectPanic("Foreach", func() { pipe.Foreach(nil) })
+	expectPanic("Map", func() { pipe.Map(nil) })
+
+	if err := pipe.Reduce(nil); err == nil {
+		t.Errorf("TestNilInstructions(); expected error from Reduce() on nil function")
+	}
+
+	if len(pipe.orders) != 0 {
+		t.Errorf("TestNilInstructions(); nil functions should not register orders. Got: [%v]\n", len(pipe.orders))
+	}
+}
+
+func TestRequireOrders(t *testing.T) {
+	numbers := []int{1, 2, 3}
+	var pipe Pipeline[int]
+
+	if _, err := pipe.Apply(numbers, Opt_RequireOrders); err == nil {
+		t.Errorf("TestRequireOrders(); expected error from Apply() on empty pipeline")
+	}
+
+	if _, err := pipe.Apply(numbers); err != nil {
+		t.Errorf("TestRequireOrders(); default should stay permissive. Got: %v", err)
+	}
+
+	pipe.Take(1)
+
+	if _, err := pipe.Apply(numbers, Opt_RequireOrders); err != nil {
+		t.Errorf("TestRequireOrders(); error from Apply(): %v", err)
+	}
+}
+
+func TestForeachIndexed(t *testing.T) {
+	numbers := []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}
+	var pipe Pipeline[int]
+
+	gotten := make([]Pair[int, int], len(numbers))
+
+	pipe.ForeachIndexed(func(index int, value int) {
+		gotten[index] = Pair[int, int]{index, value} // each index written once; safe under Opt_CFE
+	})
+
+	expected := Enumerate(numbers)
+
+	for _, opts := range [][]Option{nil, {Opt_CFE}} {
+		if _, err := pipe.Apply(numbers, opts...); err != nil {
+			t.Errorf("TestForeachIndexed(); error from Apply(): %v", err)
+		}
+
+		if !slices.Equal(expected, gotten) {
+			t.Errorf("TestForeachIndexed(); value mismatch with options %v.\nExpected: [%v] Got: [%v]\n", opts, expected, gotten)
+		}
+
+		clear(gotten)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	var pipe Pipeline[int]
+
+	if _, err := pipe.Apply(nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("TestSentinelErrors(); expected ErrEmptyInput. Got: %v", err)
+	}
+
+	if _, err := pipe.Apply([]int{1}, Opt_Clone, Opt_DPC); !errors.Is(err, ErrMultipleCloneOpts) {
+		t.Errorf("TestSentinelErrors(); expected ErrMultipleCloneOpts. Got: %v", err)
+	}
+
+	if _, err := pipe.Apply([]int{1}, Opt_Power25, Opt_Power75); !errors.Is(err, ErrMultiplePowerOpts) {
+		t.Errorf("TestSentinelErrors(); expected ErrMultiplePowerOpts. Got: %v", err)
+	}
+
+	if _, err := pipe.Apply([]int{1}, Opt_CollectErrors); !errors.Is(err, ErrUnsupportedOption) {
+		t.Errorf("TestSentinelErrors(); expected ErrUnsupportedOption from Apply(). Got: %v", err)
+	}
+	if _, _, err := Tee([]int{1}, &pipe, &pipe, Opt_CollectErrors); !errors.Is(err, ErrUnsupportedOption) {
+		t.Errorf("TestSentinelErrors(); expected ErrUnsupportedOption from Tee(). Got: %v", err)
+	}
+
+	sum := func(acc, value int) int { return acc + value }
+	pipe.Reduce(sum)
+	if err := pipe.Reduce(sum); !errors.Is(err, ErrReduceAlreadySet) {
+		t.Errorf("TestSentinelErrors(); expected ErrReduceAlreadySet. Got: %v", err)
+	}
+
+	err := pipe.Skip(-1)
+	if !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("TestSentinelErrors(); expected ErrInvalidCount. Got: %v", err)
+	}
+
+	if !strings.HasPrefix(err.Error(), "Skip(-1): No order submitted") {
+		t.Errorf("TestSentinelErrors(); human-readable message changed. Got: %v", err)
+	}
+}
+
+func TestReduceHere(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	var pipe Pipeline[int]
+
+	pipe.ReduceHere(func(acc, value int) int {
+		return acc + value
+	})
+
+	pipe.Map(func(_, value int) int {
+		return value + 1
+	})
+
+	out, err := pipe.Apply(numbers)
+	if err != nil {
+		t.Errorf("TestReduceHere(); error from Apply(): %v", err)
+	}
+
+	if !slices.Equal(out, []int{56}) {
+		t.Errorf("TestReduceHere(); value inequality.\nExpected [56] Got: [%v]\n", out)
+	}
+
+	if pipe.orders[0].method != "reduceHere" {
+		t.Errorf("TestReduceHere(); order moved.\nExpected: [reduceHere] Got: [%v]\n", pipe.orders[0].method)
+	}
+}
+
+func TestNoReduceReorder(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	var pipe Pipeline[int]
+
+	pipe.Reduce(func(acc, value int) int {
+		return acc + value
+	})
+
+	filtered := 0
+	pipe.Filter(func(value int) bool {
+		filtered++
+		return value > 50
+	})
+
+	out, err := pipe.Apply(numbers, Opt_NoReduceReorder)
+	if err != nil {
+		t.Errorf("TestNoReduceReorder(); error from Apply(): %v", err)
+	}
+
+	if !slices.Equal(out, []int{55}) {
+		t.Errorf("TestNoReduceReorder(); value inequality.\nExpected [55] Got: [%v]\n", out)
+	}
+
+	if filtered != 1 {
+		t.Errorf("TestNoReduceReorder(); filter should see only the reduced element.\nExpected: [1] Got: [%v]\n", filtered)
+	}
+
+	if pipe.orders[0].method != "reduce" {
+		t.Errorf("TestNoReduceReorder(); orders rewritten.\nExpected: [reduce] Got: [%v]\n", pipe.orders[0].method)
+	}
+}
+
+func TestFilterOrderAcrossWorkers(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+
+	numbers := make([]int, 100_000)
+	for idx := range numbers {
+		numbers[idx] = idx
+	}
+
+	var pipe Pipeline[int]
+
+	pipe.Filter(func(value int) bool {
+		return value%3 == 0
+	})
+
+	gotten, err := pipe.Apply(numbers)
+	if err != nil {
+		t.Fatalf("TestFilterOrderAcrossWorkers(); error from Apply(): %v", err)
+	}
+
+	if len(gotten) != 33_334 {
+		t.Errorf("TestFilterOrderAcrossWorkers(); length mismatch.\nExpected: [33334] Got: [%v]\n", len(gotten))
+	}
+
+	for idx, val := range gotten {
+		if val != idx*3 {
+			t.Fatalf("TestFilterOrderAcrossWorkers(); order mismatch at %v.\nExpected: [%v] Got: [%v]\n", idx, idx*3, val)
+		}
+	}
+}
+
+func TestMapForeachAcrossWorkers(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+
+	numbers := make([]int, 100_000)
+	for idx := range numbers {
+		numbers[idx] = idx
+	}
+
+	seen := make([]atomic.Int32, len(numbers))
+	var pipe Pipeline[int]
+
+	pipe.Map(func(index, value int) int {
+		if index != value {
+			t.Errorf("TestMapForeachAcrossWorkers(); index mismatch.\nExpected: [%v] Got: [%v]\n", value, index)
+		}
+		return value * 2
+	})
+
+	pipe.Foreach(func(value int) {
+		seen[value/2].Add(1)
+	})
+
+	gotten, err := pipe.Apply(numbers, Opt_CFE)
+	if err != nil {
+		t.Fatalf("TestMapForeachAcrossWorkers(); error from Apply(): %v", err)
+	}
+
+	for idx, val := range gotten {
+		if val != idx*2 {
+			t.Fatalf("TestMapForeachAcrossWorkers(); value mismatch at %v.\nExpected: [%v] Got: [%v]\n", idx, idx*2, val)
+		}
+	}
+
+	for idx := range seen {
+		if count := seen[idx].Load(); count != 1 {
+			t.Fatalf("TestMapForeachAcrossWorkers(); element %v visited %v times, expected once", idx, count)
+		}
+	}
+}
+
+func TestStringCloneStrategy(t *testing.T) {
+	var pipe Pipeline[int]
+
+	pipe.Map(func(_, value int) int {
+		return value
+	}, "Identity")
+
+	if out := pipe.String(); !strings.HasPrefix(out, "Clone: auto (Opt_Clone for []int)\n") {
+		t.Errorf("TestStringCloneStrategy(); unexpected default header.\nGot: [%v]\n", out)
+	}
+
+	pipe.CloneWith(slices.Clone[[]int])
+
+	out := pipe.String()
+	if !strings.HasPrefix(out, "Clone: custom\n") {
+		t.Errorf("TestStringCloneStrategy(); unexpected custom header.\nGot: [%v]\n", out)
+	}
+
+	if !strings.Contains(out, "Order 1:\n\tAdapter: map\n\tIndex: 0\n\tComments: \n\t\t[ Identity ]") {
+		t.Errorf("TestStringCloneStrategy(); order formatting changed.\nGot: [%v]\n", out)
+	}
+}
+
+func TestCloneWith(t *testing.T) {
+	numbers := []int{1, 2, 3}
+	var pipe Pipeline[int]
+
+	calls := 0
+	pipe.CloneWith(func(input []int) []int {
+		calls++
+		return slices.Clone(input)
+	})
+
+	pipe.Map(func(_, value int) int {
+		return value * 2
+	})
+
+	out, err := pipe.Apply(numbers)
+	if err != nil {
+		t.Errorf("TestCloneWith(); error from Apply(): %v", err)
+	}
+
+	if calls != 1 || !slices.Equal(out, []int{2, 4, 6}) || !slices.Equal(numbers, []int{1, 2, 3}) {
+		t.Errorf("TestCloneWith(); custom clone not used correctly. Calls: [%v] Out: [%v] In: [%v]\n", calls, out, numbers)
+	}
+
+	pipe.Apply(numbers, Opt_Clone)
+
+	if calls != 1 {
+		t.Errorf("TestCloneWith(); explicit clone option should take precedence over the hook")
+	}
+}
+
+func TestWithSeed(t *testing.T) {
+	numbers := make([]int, 1000)
+	for idx := range numbers {
+		numbers[idx] = idx
+	}
+
+	build := func(seed int64) []int {
+		var pipe Pipeline[int]
+
+		pipe.WithSeed(seed)
+		pipe.Sample(100, 1)
+		pipe.Shuffle(2)
+
+		out, err := pipe.Apply(numbers)
+		if err != nil {
+			t.Fatalf("TestWithSeed(); error from Apply(): %v", err)
+		}
+		return out
+	}
+
+	first, second := build(99), build(99)
+
+	if !slices.Equal(first, second) {
+		t.Errorf("TestWithSeed(); same seed produced different output.\nFirst: [%v] Second: [%v]\n", first, second)
+	}
+
+	if slices.Equal(first, build(100)) {
+		t.Errorf("TestWithSeed(); different seeds produced identical output")
+	}
+}
+
+func TestSingleElementInput(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+
+	var pipe Pipeline[int]
+
+	pipe.Map(func(_, value int) int {
+		return value * 3
+	})
+
+	pipe.Filter(func(value int) bool {
+		return value > 0
+	})
+
+	gotten, err := pipe.Apply([]int{7}, Opt_CFE)
+	if err != nil {
+		t.Errorf("TestSingleElementInput(); error from Apply(): %v", err)
+	}
+
+	if !slices.Equal(gotten, []int{21}) {
+		t.Errorf("TestSingleElementInput(); value mismatch.\nExpected: [[21]] Got: [%v]\n", gotten)
+	}
+}
+
+func TestZeroWorkers(t *testing.T) {
+	covered := make([]int, 10)
+
+	parallelChunks(len(covered), 0, func(worker, start, end int) {
+		for idx := start; idx < end; idx++ {
+			covered[idx]++
+		}
+	})
+
+	if !slices.Equal(covered, []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}) {
+		t.Errorf("TestZeroWorkers(); zero workers should fall back to one.\nGot: [%v]\n", covered)
+	}
+
+	parallelChunks(0, 4, func(worker, start, end int) {
+		t.Errorf("TestZeroWorkers(); no chunk expected for empty input. Got: [%v, %v)", start, end)
+	})
+}
+
+func TestSerialThreshold(t *testing.T) {
+	var pipeline Pipeline[int]
+
+	if err := pipeline.SetSerialThreshold(-1); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("TestSerialThreshold(); expected ErrInvalidCount. Got: [%v]\n", err)
+	}
+
+	pipeline.Filter(func(value int) bool { return value%3 == 0 })
+	pipeline.Map(func(index int, value int) int { return value * 2 })
+
+	input := benchmarkInput(5000)
+	var expected []int
+	for _, v := range input {
+		if v%3 == 0 {
+			expected = append(expected, v*2)
+		}
+	}
+
+	for _, threshold := range []int{0, 100, 1 << 20} {
+		if err := pipeline.SetSerialThreshold(threshold); err != nil {
+			t.Fatal(err)
+		}
+
+		gotten, err := pipeline.Apply(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(gotten, expected) {
+			t.Errorf("TestSerialThreshold(); value mismatch at threshold %v.\n", threshold)
+		}
+	}
+}
+
+func benchmarkSmallApply(b *testing.B, threshold int) {
+	var pipeline Pipeline[int]
+	pipeline.Filter(func(value int) bool { return value%2 == 0 })
+	pipeline.Map(func(index int, value int) int { return value + 1 })
+	pipeline.SetSerialThreshold(threshold)
+
+	input := benchmarkInput(100)
+
+	for b.Loop() {
+		pipeline.Apply(input)
+	}
+}
+
+func BenchmarkApplySmallParallel(b *testing.B) { benchmarkSmallApply(b, 0) }
+func BenchmarkApplySmallSerial(b *testing.B)   { benchmarkSmallApply(b, defaultSerialThreshold) }
+
+func TestMapInPlace(t *testing.T) {
+	type person struct {
+		Name string
+		Age  int
+	}
+
+	var pipeline Pipeline[person]
+	pipeline.Map(func(index int, value person) person {
+		value.Age++
+		return value
+	})
+
+	input := []person{{"Bob", 30}, {"Alice", 40}}
+	gotten, err := pipeline.Apply(input, Opt_MapInPlace)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []person{{"Bob", 31}, {"Alice", 41}}
+	if !slices.Equal(gotten, expected) {
+		t.Errorf("TestMapInPlace(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+	if &gotten[0] != &input[0] {
+		t.Errorf("TestMapInPlace(); expected the input backing array to be reused.\n")
+	}
+
+	if _, err := pipeline.Apply(input, Opt_MapInPlace, Opt_Clone); !errors.Is(err, ErrMultipleCloneOpts) {
+		t.Errorf("TestMapInPlace(); expected ErrMultipleCloneOpts. Got: [%v]\n", err)
+	}
+
+	pipeline.Filter(func(value person) bool { return value.Age > 35 })
+	if _, err := pipeline.Apply(input, Opt_MapInPlace); !errors.Is(err, ErrNotMapOnly) {
+		t.Errorf("TestMapInPlace(); expected ErrNotMapOnly. Got: [%v]\n", err)
+	}
+	if !slices.Equal(input, expected) {
+		t.Errorf("TestMapInPlace(); rejected pipeline should leave input alone. Got: [%v]\n", input)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var valid Pipeline[int]
+	valid.Filter(func(value int) bool { return value > 0 })
+	valid.Map(func(index int, value int) int { return value })
+	valid.Reduce(func(acc, value int) int { return acc + value })
+	valid.Take(1)
+
+	if err := valid.Validate(); err != nil {
+		t.Errorf("TestValidate(); unexpected error on a valid pipeline: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		build    func(p *Pipeline[int])
+		expected error
+	}{
+		{"index out of range", func(p *Pipeline[int]) {
+			p.Map(func(index int, value int) int { return value })
+			p.orders[0].index = 3
+		}, ErrOutOfRange},
+		{"negative index", func(p *Pipeline[int]) {
+			p.Skip(2)
+			p.orders[0].index = -1
+		}, ErrOutOfRange},
+		{"nil filter", func(p *Pipeline[int]) {
+			p.Filter(func(value int) bool { return true })
+			p.filterInstructs[0] = nil
+		}, ErrNilFunc},
+		{"nil reduce", func(p *Pipeline[int]) {
+			p.orders = append(p.orders, order{method: "reduce"})
+		}, ErrNilFunc},
+		{"two reduces", func(p *Pipeline[int]) {
+			p.Reduce(func(acc, value int) int { return acc })
+			p.orders = append(p.orders, p.orders[0])
+		}, ErrReduceAlreadySet},
+	}
+
+	for _, test := range tests {
+		var pipeline Pipeline[int]
+		test.build(&pipeline)
+
+		if err := pipeline.Validate(); !errors.Is(err, test.expected) {
+			t.Errorf("TestValidate(); %v: expected [%v]. Got: [%v]\n", test.name, test.expected, err)
+		}
+	}
+
+	var unknown Pipeline[int]
+	unknown.orders = append(unknown.orders, order{method: "bogus"})
+	if err := unknown.Validate(); err == nil {
+		t.Errorf("TestValidate(); expected an error for an unknown method.\n")
+	}
+}
+
+func TestClonePool(t *testing.T) {
+	input := make([]*int, 10)
+	for idx := range input {
+		input[idx] = new(int)
+		*input[idx] = idx
+	}
+
+	var pipeline Pipeline[*int]
+	pipeline.Filter(func(value *int) bool { return *value%2 == 0 })
+	pipeline.Map(func(index int, value *int) *int {
+		*value *= 10
+		return value
+	})
+
+	first, err := pipeline.Apply(input, Opt_ClonePool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := pipeline.Apply(input, Opt_ClonePool)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for idx, val := range input {
+		if *val != idx {
+			t.Errorf("TestClonePool(); input pointee %v mutated. Got: [%v]\n", idx, *val)
+		}
+	}
+
+	for idx := range first {
+		if *first[idx] != idx*20 || *second[idx] != idx*20 {
+			t.Errorf("TestClonePool(); value mismatch at %v.\nExpected: [%v] Got: [%v, %v]\n", idx, idx*20, *first[idx], *second[idx])
+		}
+		if first[idx] == second[idx] {
+			t.Errorf("TestClonePool(); results of separate runs share pointee %v.\n", idx)
+		}
+	}
+
+	if _, err := pipeline.Apply(input, Opt_ClonePool, Opt_Clone); !errors.Is(err, ErrMultipleCloneOpts) {
+		t.Errorf("TestClonePool(); expected ErrMultipleCloneOpts. Got: [%v]\n", err)
+	}
+}
+
+func benchmarkPooledLoop(b *testing.B, opt Option) {
+	var pipeline Pipeline[int]
+	pipeline.Filter(func(value int) bool { return value%10 == 0 })
+
+	input := benchmarkInput(10_000)
+	b.ReportAllocs()
+
+	for b.Loop() {
+		for range 1000 {
+			pipeline.Apply(input, opt)
+		}
+	}
+}
+
+func BenchmarkApplyLoopClone(b *testing.B)     { benchmarkPooledLoop(b, Opt_Clone) }
+func BenchmarkApplyLoopClonePool(b *testing.B) { benchmarkPooledLoop(b, Opt_ClonePool) }
+
+func TestApplyInto(t *testing.T) {
+	input := []*int{new(int), new(int), new(int)}
+	for idx, val := range input {
+		*val = idx
+	}
+
+	var pipeline Pipeline[*int]
+	pipeline.Map(func(index int, value *int) *int {
+		*value *= 10
+		return value
+	})
+
+	dst := make([]*int, 0, 8)
+
+	out, err := pipeline.ApplyInto(dst, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(out) != 3 || *out[0] != 0 || *out[1] != 10 || *out[2] != 20 {
+		t.Errorf("TestApplyInto(); value mismatch.\nExpected: [0 10 20] Got: [%v]\n", out)
+	}
+	if &out[0] != &dst[:1][0] {
+		t.Errorf("TestApplyInto(); expected result to reuse dst's backing array")
+	}
+	for idx, val := range input {
+		if *val != idx || out[idx] == val {
+			t.Errorf("TestApplyInto(); input element %v shared or mutated. Got: [%v]\n", idx, *val)
+		}
+	}
+
+	// too small, so it grows
+	pipeline.Filter(func(value *int) bool { return *value > 0 })
+
+	out, err = pipeline.ApplyInto(make([]*int, 0, 1), input)
+	if err != nil || len(out) != 2 || *out[0] != 10 || *out[1] != 20 {
+		t.Errorf("TestApplyInto(); grown value mismatch.\nExpected: [10 20] Got: [%v %v]\n", out, err)
+	}
+
+	var ints Pipeline[int]
+	ints.Map(func(index int, value int) int { return value + 1 })
+	ints.Filter(func(value int) bool { return value%2 == 0 })
+
+	// aliasing is allowed under Opt_InPlace
+	numbers := []int{1, 2, 3, 4, 5}
+	got, err := ints.ApplyInto(numbers, numbers, Opt_InPlace)
+	if err != nil || !slices.Equal(got, []int{2, 4, 6}) {
+		t.Errorf("TestApplyInto(); Opt_InPlace mismatch.\nExpected: [[2 4 6]] Got: [%v %v]\n", got, err)
+	}
+}
+
+func benchmarkApplyInto(b *testing.B, into bool) {
+	var pipeline Pipeline[int]
+	pipeline.Map(func(index int, value int) int { return value * 2 })
+
+	input := benchmarkInput(512)
+	dst := make([]int, 0, len(input))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if into {
+			dst, _ = pipeline.ApplyInto(dst, input)
+		} else {
+			pipeline.Apply(input)
+		}
+	}
+}
+
+func BenchmarkApplyMapOnly(b *testing.B) { benchmarkApplyInto(b, false) }
+func BenchmarkApplyInto(b *testing.B)    { benchmarkApplyInto(b, true) }
+
+func TestReduceIndexed(t *testing.T) {
+	weighted := func(acc int, index int, value int) int { return acc + index*value }
+
+	var pipeline Pipeline[int]
+	if err := pipeline.ReduceIndexed(weighted); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := pipeline.Apply([]int{1, 1, 1, 1})
+	if err != nil || !slices.Equal(out, []int{6}) {
+		t.Errorf("TestReduceIndexed(); value mismatch.\nExpected: [[6]] Got: [%v %v]\n", out, err)
+	}
+
+	// indexes are positions after the filter, fused or not
+	pipeline.Filter(func(value int) bool { return value%2 == 0 })
+
+	out, err = pipeline.Apply([]int{2, 1, 2, 3, 2, 5})
+	if err != nil || !slices.Equal(out, []int{6}) {
+		t.Errorf("TestReduceIndexed(); filtered value mismatch.\nExpected: [[6]] Got: [%v %v]\n", out, err)
+	}
+
+	if err := pipeline.Reduce(func(acc, value int) int { return acc }); !errors.Is(err, ErrReduceAlreadySet) {
+		t.Errorf("TestReduceIndexed(); shared slot.\nExpected: [%v] Got: [%v]\n", ErrReduceAlreadySet, err)
+	}
+}
+
+func TestReduceWhile(t *testing.T) {
+	var calls int
+	and := func(acc, value bool) (bool, bool) {
+		calls++
+		acc = acc && value
+		return acc, acc
+	}
+
+	var pipeline Pipeline[bool]
+	if err := pipeline.ReduceWhile(and); err != nil {
+		t.Fatal(err)
+	}
+
+	input := []bool{true, true, false, true, true, true}
+
+	out, err := pipeline.Apply(input)
+	if err != nil || !slices.Equal(out, []bool{false}) {
+		t.Errorf("TestReduceWhile(); value mismatch.\nExpected: [[false]] Got: [%v %v]\n", out, err)
+	}
+	if calls != 2 {
+		t.Errorf("TestReduceWhile(); should stop at the first false.\nExpected: [2 calls] Got: [%v]\n", calls)
+	}
+
+	// fused behind a filter, the stop still holds across worker chunks
+	var capped Pipeline[int]
+	capped.SetSerialThreshold(0)
+	capped.Filter(func(value int) bool { return value%2 == 0 })
+	capped.ReduceWhile(func(acc, value int) (int, bool) {
+		return acc + value, acc+value < 100
+	})
+
+	sum, err := capped.Apply(Range(10_000))
+	if err != nil || !slices.Equal(sum, []int{110}) {
+		t.Errorf("TestReduceWhile(); capped value mismatch.\nExpected: [[110]] Got: [%v %v]\n", sum, err)
+	}
+
+	if err := pipeline.ReduceWhile(and); !errors.Is(err, ErrReduceAlreadySet) {
+		t.Errorf("TestReduceWhile(); shared slot.\nExpected: [%v] Got: [%v]\n", ErrReduceAlreadySet, err)
+	}
+}
+
+func TestReduceRight(t *testing.T) {
+	sub := func(acc, value int) int { return acc - value }
+
+	var left, right Pipeline[int]
+	left.Reduce(sub)
+	right.ReduceRight(sub)
+
+	gottenLeft, err := left.Apply([]int{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gottenRight, err := right.Apply([]int{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(gottenLeft, []int{-4}) {
+		t.Errorf("TestReduceRight(); left value mismatch.\nExpected: [[-4]] Got: [%v]\n", gottenLeft)
+	}
+	if !slices.Equal(gottenRight, []int{0}) {
+		t.Errorf("TestReduceRight(); right value mismatch.\nExpected: [[0]] Got: [%v]\n", gottenRight)
+	}
+
+	if err := right.Reduce(sub); !errors.Is(err, ErrReduceAlreadySet) {
+		t.Errorf("TestReduceRight(); expected ErrReduceAlreadySet. Got: [%v]\n", err)
+	}
+	if err := left.ReduceRight(sub); !errors.Is(err, ErrReduceAlreadySet) {
+		t.Errorf("TestReduceRight(); expected ErrReduceAlreadySet. Got: [%v]\n", err)
+	}
+	if err := left.ReduceRight(nil); !errors.Is(err, ErrNilFunc) {
+		t.Errorf("TestReduceRight(); expected ErrNilFunc. Got: [%v]\n", err)
+	}
+}
+
+func TestApplyWithRejects(t *testing.T) {
+	var pipeline Pipeline[int]
+	pipeline.Map(func(index int, value int) int { return value + 1 })
+	pipeline.Filter(func(value int) bool { return value%2 == 0 })
+
+	kept, rejected, err := pipeline.ApplyWithRejects([]int{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(kept, []int{2, 4, 6}) {
+		t.Errorf("TestApplyWithRejects(); kept mismatch.\nExpected: [[2 4 6]] Got: [%v]\n", kept)
+	}
+
+	if len(rejected) != 2 || rejected[0] != nil || !slices.Equal(rejected[1], []int{3, 5}) {
+		t.Errorf("TestApplyWithRejects(); rejected mismatch.\nExpected: [[[] [3 5]]] Got: [%v]\n", rejected)
+	}
+
+	if pipeline.rejects != nil {
+		t.Errorf("TestApplyWithRejects(); reject collection left switched on.\n")
+	}
+
+	var pointers Pipeline[*int]
+	pointers.Filter(func(value *int) bool { return *value%2 == 0 })
+
+	input := []*int{new(int), new(int)}
+	*input[1] = 1
+
+	_, rejectedPtrs, err := pointers.ApplyWithRejects(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rejectedPtrs[0]) != 1 || rejectedPtrs[0][0] == input[1] {
+		t.Errorf("TestApplyWithRejects(); rejects of a leading filter alias the input.\n")
+	}
+}
+
+func TestCollect(t *testing.T) {
+	var afterFilter, afterMap []int
+
+	var pipeline Pipeline[int]
+	pipeline.Filter(func(value int) bool { return value > 2 })
+	pipeline.Collect(&afterFilter, "after filter")
+	pipeline.Map(func(index int, value int) int { return value * 100 })
+	pipeline.Collect(&afterMap)
+	pipeline.Take(1)
+
+	gotten, err := pipeline.Apply([]int{1, 2, 3, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(afterFilter, []int{3, 4}) {
+		t.Errorf("TestCollect(); snapshot mismatch.\nExpected: [[3 4]] Got: [%v]\n", afterFilter)
+	}
+	if !slices.Equal(afterMap, []int{300, 400}) {
+		t.Errorf("TestCollect(); snapshot mismatch.\nExpected: [[300 400]] Got: [%v]\n", afterMap)
+	}
+	if !slices.Equal(gotten, []int{300}) {
+		t.Errorf("TestCollect(); value mismatch.\nExpected: [[300]] Got: [%v]\n", gotten)
+	}
+
+	if err := pipeline.Validate(); err != nil {
+		t.Errorf("TestCollect(); unexpected Validate() error: %v", err)
+	}
+}
+
+func TestStringsShallowCopy(t *testing.T) {
+	input := []string{"derp", "is", "reusable"}
+
+	var pipe Pipeline[string]
+	pipe.Map(func(index int, value string) string { return strings.ToUpper(value) })
+
+	allocs := testing.AllocsPerRun(100, func() {
+		pipe.cloneInput(input, nil)
+	})
+	if allocs != 1 {
+		t.Errorf("TestStringsShallowCopy(); expected a single allocation for the copy. Got: [%v]\n", allocs)
+	}
+
+	gotten, err := pipe.Apply(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(input, []string{"derp", "is", "reusable"}) || !slices.Equal(gotten, []string{"DERP", "IS", "REUSABLE"}) {
+		t.Errorf("TestStringsShallowCopy(); value mismatch. Input: [%v] Got: [%v]\n", input, gotten)
+	}
+
+	if out := pipe.String(); !strings.HasPrefix(out, "Clone: auto (shallow copy for []string)\n") {
+		t.Errorf("TestStringsShallowCopy(); unexpected header. Got: [%v]\n", out)
+	}
+}
+
+type fuzzRecord struct {
+	ID     int
+	Name   string
+	Tags   []string
+	Attrs  map[string]int
+	Scores [2]float64
+	Next   *fuzzRecord
+}
+
+// Deterministically turns fuzz bytes into values, so the same data always builds an equal but independent copy.
+type fuzzSource struct {
+	data []byte
+	pos  int
+}
+
+func (src *fuzzSource) next() int {
+	if len(src.data) == 0 {
+		return 0
+	}
+	b := src.data[src.pos%len(src.data)]
+	src.pos++
+	return int(b)
+}
+
+func (src *fuzzSource) record(depth int) fuzzRecord {
+	rec := fuzzRecord{
+		ID:     src.next(),
+		Name:   strconv.Itoa(src.next()),
+		Scores: [2]float64{float64(src.next()) / 4, float64(src.next())},
+	}
+
+	for range src.next() % 4 {
+		rec.Tags = append(rec.Tags, strconv.Itoa(src.next()))
+	}
+
+	if n := src.next() % 4; n > 0 {
+		rec.Attrs = make(map[string]int, n)
+		for range n {
+			rec.Attrs[strconv.Itoa(src.next())] = src.next()
+		}
+	}
+
+	if depth < 2 && src.next()%2 == 0 {
+		next := src.record(depth + 1)
+		rec.Next = &next
+	}
+
+	return rec
+}
+
+func (src *fuzzSource) records() []fuzzRecord {
+	out := make([]fuzzRecord, 1+src.next()%8)
+	for idx := range out {
+		out[idx] = src.record(0)
+	}
+	return out
+}
+
+func scribble(rec *fuzzRecord) {
+	for ; rec != nil; rec = rec.Next {
+		rec.ID = -1
+		rec.Name += "!"
+		rec.Scores[0] = -1
+		for idx := range rec.Tags {
+			rec.Tags[idx] = "scribbled"
+		}
+		if rec.Attrs != nil {
+			rec.Attrs["scribbled"] = -1
+		}
+	}
+}
+
+func FuzzApplyImmutability(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{3, 1, 2, 3, 4, 5, 6, 7, 0})
+	f.Add([]byte("derp is reusable"))
+	f.Add([]byte{7, 255, 0, 128, 3, 3, 3, 2, 2, 9, 0, 0, 1})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		input := (&fuzzSource{data: data}).records()
+		expected := (&fuzzSource{data: data}).records()
+
+		var values Pipeline[fuzzRecord]
+		values.Map(func(index int, value fuzzRecord) fuzzRecord {
+			scribble(&value)
+			return value
+		})
+
+		if _, err := values.Apply(input); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(input, expected) {
+			t.Fatalf("FuzzApplyImmutability(); []fuzzRecord input mutated.\nExpected: [%+v] Got: [%+v]\n", expected, input)
+		}
+
+		pointers := make([]*fuzzRecord, len(input))
+		for idx := range input {
+			pointers[idx] = &input[idx]
+		}
+
+		var pointees Pipeline[*fuzzRecord]
+		pointees.Map(func(index int, value *fuzzRecord) *fuzzRecord {
+			scribble(value)
+			return value
+		})
+
+		if _, err := pointees.Apply(pointers); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(input, expected) {
+			t.Fatalf("FuzzApplyImmutability(); []*fuzzRecord pointees mutated.\nExpected: [%+v] Got: [%+v]\n", expected, input)
+		}
+	})
+}
+
+func TestDeterministic(t *testing.T) {
+	var pipeline Pipeline[int]
+	pipeline.SetSerialThreshold(0)
+	pipeline.Filter(func(value int) bool { return value%3 != 0 })
+	pipeline.Map(func(index int, value int) int { return value ^ index })
+	pipeline.SortByKeys([]func(a, b int) int{func(a, b int) int { return a%100 - b%100 }})
+
+	var seen []int
+	pipeline.Foreach(func(value int) { seen = append(seen, value) })
+
+	input := benchmarkInput(20_000)
+
+	concurrent, err := pipeline.Apply(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen = nil
+	serial, err := pipeline.Apply(input, Opt_Deterministic, Opt_CFE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(concurrent, serial) {
+		t.Errorf("TestDeterministic(); output differs with Opt_Deterministic.\n")
+	}
+	if !slices.Equal(seen, serial) {
+		t.Errorf("TestDeterministic(); Foreach under Opt_CFE did not run in index order.\n")
+	}
+
+	if workers := workerCount([]Option{Opt_Power100, Opt_Deterministic}); workers != 1 {
+		t.Errorf("TestDeterministic(); expected 1 worker. Got: [%v]\n", workers)
+	}
+}
+
+func TestApplyChunked(t *testing.T) {
+	input := Range(5000)
+
+	var filterLast Pipeline[int]
+	filterLast.SetSerialThreshold(0)
+	filterLast.Map(func(index int, value int) int { return value * 3 })
+	filterLast.Filter(func(value int) bool { return value%2 == 0 })
+
+	var mapLast Pipeline[int]
+	mapLast.SetSerialThreshold(0)
+	mapLast.Filter(func(value int) bool { return value%7 != 0 })
+	mapLast.Map(func(index int, value int) int { return value + index })
+
+	for _, pipeline := range []*Pipeline[int]{&filterLast, &mapLast} {
+		expected, err := pipeline.Apply(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chunks, err := pipeline.ApplyChunked(input, Opt_Power100)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if gotten := Flatten(chunks); !slices.Equal(gotten, expected) {
+			t.Errorf("TestApplyChunked(); flattened chunks differ from Apply().\nExpected: [%v] Got: [%v]\n", len(expected), len(gotten))
+		}
+
+		if len(chunks) > runtime.GOMAXPROCS(0) {
+			t.Errorf("TestApplyChunked(); more chunks than workers. Got: [%v]\n", len(chunks))
+		}
+	}
+
+	// the unordered filter's chunks live in the pooled buffer, which is recycled before returning
+	expected, _ := filterLast.Apply(input)
+	chunks, err := filterLast.ApplyChunked(input, Opt_ClonePool, Opt_UnorderedFast)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotten := Flatten(chunks)
+	slices.Sort(gotten)
+	if !slices.Equal(gotten, expected) {
+		t.Errorf("TestApplyChunked(); pooled unordered chunks differ from Apply().\nExpected: [%v ...] Got: [%v ...]\n", expected[:3], gotten[:min(3, len(gotten))])
+	}
+}
+
+func TestApplyBatched(t *testing.T) {
+	var pipeline Pipeline[int]
+	pipeline.Filter(func(value int) bool { return value%3 == 0 })
+	pipeline.Map(func(index int, value int) int { return value * value })
+
+	input := Range(1000)
+
+	expected, err := pipeline.Apply(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, batchSize := range []int{1, 7, 128, 1000, 5000} {
+		gotten, err := pipeline.ApplyBatched(input, batchSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !slices.Equal(gotten, expected) {
+			t.Errorf("TestApplyBatched(); value mismatch at batch size %v.\n", batchSize)
+		}
+	}
+
+	if _, err := pipeline.ApplyBatched(input, 0); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("TestApplyBatched(); expected ErrInvalidCount. Got: [%v]\n", err)
+	}
+
+	pipeline.Take(10)
+	if _, err := pipeline.ApplyBatched(input, 100); !errors.Is(err, ErrStatefulOrder) {
+		t.Errorf("TestApplyBatched(); expected ErrStatefulOrder. Got: [%v]\n", err)
+	}
+}
+
+func TestPreserveOrder(t *testing.T) {
+	input := Generate(1000, func(index int) int { return index + 1 })
+
+	var pipeline Pipeline[int]
+	pipeline.SetSerialThreshold(0)
+	pipeline.Filter(func(value int) bool { return value%2 == 0 })
+
+	for _, opts := range [][]Option{nil, {Opt_PreserveOrder}, {Opt_PreserveOrder, Opt_Power25}} {
+		gotten, err := pipeline.Apply(input, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(gotten) != 500 {
+			t.Errorf("TestPreserveOrder(); expected 500 evens with %v. Got: [%v]\n", opts, len(gotten))
+		}
+		for idx := 1; idx < len(gotten); idx++ {
+			if gotten[idx] <= gotten[idx-1] {
+				t.Errorf("TestPreserveOrder(); not strictly increasing at %v with %v.\n", idx, opts)
+				break
+			}
+		}
+	}
+
+	gotten, err := pipeline.Apply(input, Opt_UnorderedFast)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slices.Sort(gotten)
+	expected := Generate(500, func(index int) int { return (index + 1) * 2 })
+	if !slices.Equal(gotten, expected) {
+		t.Errorf("TestPreserveOrder(); Opt_UnorderedFast kept the wrong elements.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if _, err := pipeline.Apply(input, Opt_PreserveOrder, Opt_UnorderedFast); !errors.Is(err, ErrMultipleOrderOpts) {
+		t.Errorf("TestPreserveOrder(); expected ErrMultipleOrderOpts. Got: [%v]\n", err)
+	}
+}
+
+func TestFilterUnorderedSparse(t *testing.T) {
+	input := benchmarkInput(10_000)
+
+	var pipeline Pipeline[int]
+	pipeline.SetSerialThreshold(0)
+	pipeline.Filter(func(value int) bool { return value%17 == 0 })
+
+	ordered, err := pipeline.Apply(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, rejected, err := pipeline.ApplyWithRejects(input, Opt_UnorderedFast)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slices.Sort(ordered)
+	slices.Sort(kept)
+	if !slices.Equal(ordered, kept) {
+		t.Errorf("TestFilterUnorderedSparse(); survivors differ from the ordered filter.\n")
+	}
+	if len(kept)+len(rejected[0]) != len(input) {
+		t.Errorf("TestFilterUnorderedSparse(); kept and rejected don't add up. Got: [%v + %v]\n", len(kept), len(rejected[0]))
+	}
+
+	chunks, err := pipeline.ApplyChunked(input, Opt_UnorderedFast)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flat := Flatten(chunks); len(flat) != len(kept) {
+		t.Errorf("TestFilterUnorderedSparse(); chunked survivors mismatch. Expected: [%v] Got: [%v]\n", len(kept), len(flat))
+	}
+}
+
+func TestWithStageWorkers(t *testing.T) {
+	var seen []int
+
+	var pipeline Pipeline[int]
+	pipeline.SetSerialThreshold(0)
+	pipeline.Map(func(index int, value int) int { return value * 2 })
+	pipeline.Foreach(func(value int) { seen = append(seen, value) }) // unsynchronised; only safe serially
+
+	if err := pipeline.WithStageWorkers(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := pipeline.WithStageWorkers(0, 8); err != nil {
+		t.Fatal(err)
+	}
+
+	input := Range(10_000)
+	gotten, err := pipeline.Apply(input, Opt_CFE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(seen, gotten) {
+		t.Errorf("TestWithStageWorkers(); serial stage did not run in index order.\n")
+	}
+
+	if err := pipeline.WithStageWorkers(2, 1); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("TestWithStageWorkers(); expected ErrOutOfRange. Got: [%v]\n", err)
+	}
+	if err := pipeline.WithStageWorkers(0, 0); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("TestWithStageWorkers(); expected ErrInvalidCount. Got: [%v]\n", err)
+	}
+}
+
+func TestFusedFilterReduce(t *testing.T) {
+	input := benchmarkInput(10_000)
+	keep := func(value int) bool { return value%5 == 0 }
+	sub := func(acc, value int) int { return acc - value }
+
+	for _, register := range []func(*Pipeline[int]) error{
+		func(p *Pipeline[int]) error { return p.Reduce(sub) },
+		func(p *Pipeline[int]) error { return p.ReduceRight(sub) },
+		func(p *Pipeline[int]) error { return ReduceSum(p) },
+		func(p *Pipeline[int]) error {
+			return p.ReduceIndexed(func(acc, index, value int) int { return acc - index*value })
+		},
+		func(p *Pipeline[int]) error {
+			return p.ReduceWhile(func(acc, value int) (int, bool) { return acc - value, acc > -1<<60 })
+		},
+	} {
+		var fused, unfused Pipeline[int]
+		fused.SetSerialThreshold(0)
+		unfused.SetSerialThreshold(0)
+
+		fused.Filter(keep)
+		register(&fused)
+
+		unfused.Filter(keep)
+		unfused.Map(func(index int, value int) int { return value }) // keeps the filter from being fused
+		register(&unfused)
+
+		// the filter and reduce must run in the same range, or they're never fused
+		if prefix := fused.readOnlyPrefix(nil); prefix != 2 {
+			t.Fatalf("TestFusedFilterReduce(); filter split from its reduce. Got prefix: [%v]\n", prefix)
+		}
+
+		expected, err := unfused.Apply(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotten, err := fused.Apply(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !slices.Equal(gotten, expected) {
+			t.Errorf("TestFusedFilterReduce(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+		}
+	}
+
+	// the fused reduce sees clones, so a reduce writing through acc can't reach the caller's pointees
+	pointers := Generate(3000, func(index int) *int { return &index })
+
+	var total Pipeline[*int]
+	total.SetSerialThreshold(0)
+	total.Filter(func(value *int) bool { return *value%2 == 0 })
+	total.Reduce(func(acc, value *int) *int {
+		*acc += *value
+		return acc
+	})
+
+	gotten, err := total.Apply(pointers)
+	if err != nil || *gotten[0] != 2248500 {
+		t.Errorf("TestFusedFilterReduce(); pointer sum mismatch.\nExpected: [2248500] Got: [%v %v]\n", *gotten[0], err)
+	}
+	for idx, val := range pointers {
+		if *val != idx {
+			t.Fatalf("TestFusedFilterReduce(); input pointee %v mutated. Got: [%v]\n", idx, *val)
+		}
+	}
+
+	var none Pipeline[int]
+	none.Filter(func(value int) bool { return false })
+	none.Reduce(sub)
+
+	if gotten, err := none.Apply(input); err != nil || gotten == nil || len(gotten) != 0 {
+		t.Errorf("TestFusedFilterReduce(); expected an empty result. Got: [%v, %v]\n", gotten, err)
+	}
+}
+
+func benchmarkPrimeSum(b *testing.B, fuse bool) {
+	isPrime := func(value int) bool {
+		if value < 2 {
+			return false
+		}
+		for i := 2; i*i <= value; i++ {
+			if value%i == 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	var pipeline Pipeline[int]
+	pipeline.Filter(isPrime)
+	if !fuse {
+		pipeline.Map(func(index int, value int) int { return value })
+	}
+	pipeline.Reduce(func(acc, value int) int { return acc + value })
+
+	input := Generate(200_000, func(index int) int { return index + 1 })
+	b.ReportAllocs()
+
+	for b.Loop() {
+		pipeline.Apply(input)
+	}
+}
+
+func BenchmarkPrimeSumUnfused(b *testing.B) { benchmarkPrimeSum(b, false) }
+func BenchmarkPrimeSumFused(b *testing.B)   { benchmarkPrimeSum(b, true) }
+
+func TestApplyStop(t *testing.T) {
+	stop := make(chan struct{})
+	var processed atomic.Int64
+
+	var pipeline Pipeline[int]
+	pipeline.Map(func(index int, value int) int {
+		processed.Add(1)
+		time.Sleep(10 * time.Microsecond)
+		return value
+	})
+	pipeline.Filter(func(value int) bool { return true })
+
+	input := Range(1_000_000)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(stop)
+	}()
+
+	start := time.Now()
+	gotten, err := pipeline.ApplyStop(input, stop)
+
+	if !errors.Is(err, ErrStopped) || gotten != nil {
+		t.Errorf("TestApplyStop(); expected ErrStopped and no result. Got: [%v, %v]\n", len(gotten), err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("TestApplyStop(); took too long to stop: %v", elapsed)
+	}
+	if n := processed.Load(); n == int64(len(input)) {
+		t.Errorf("TestApplyStop(); every element was processed despite the stop.\n")
+	}
+
+	gotten, err = pipeline.ApplyStop([]int{1, 2, 3}, make(chan struct{}))
+	if err != nil || !slices.Equal(gotten, []int{1, 2, 3}) {
+		t.Errorf("TestApplyStop(); unsignalled run mismatch. Got: [%v, %v]\n", gotten, err)
+	}
+}
+
+func TestForeachCollect(t *testing.T) {
+	var sink []int
+
+	var pipeline Pipeline[int]
+	pipeline.SetSerialThreshold(0)
+	pipeline.Map(func(index int, value int) int { return value * 2 })
+	pipeline.ForeachCollect(&sink, "gather doubled")
+	pipeline.Filter(func(value int) bool { return value%3 == 0 })
+
+	input := Range(10_000)
+	if _, err := pipeline.Apply(input, Opt_CFE); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Generate(10_000, func(index int) int { return index * 2 })
+	if !slices.Equal(sink, expected) {
+		t.Errorf("TestForeachCollect(); collected %v elements, expected %v in order.\n", len(sink), len(expected))
+	}
+
+	if _, err := pipeline.Apply([]int{5}, Opt_CFE); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink) != 10_001 || sink[10_000] != 10 {
+		t.Errorf("TestForeachCollect(); second Apply should append. Got length [%v]\n", len(sink))
+	}
+}
+
+func TestTryMap(t *testing.T) {
+	var pipeline Pipeline[int]
+	pipeline.SetSerialThreshold(0)
+	pipeline.TryMap(func(index int, value int) (int, error) {
+		if value > 5000 {
+			return 0, ErrStop
+		}
+		return value * 2, nil
+	})
+	pipeline.Map(func(index int, value int) int { return -1 }) // never reached once stopped
+
+	gotten, err := pipeline.Apply(Range(10_000))
+	if err != nil {
+		t.Fatalf("TestTryMap(); ErrStop should not surface. Got: %v", err)
+	}
+
+	expected := Generate(5001, func(index int) int { return index * 2 })
+	if !slices.Equal(gotten, expected) {
+		t.Errorf("TestTryMap(); truncated result mismatch. Expected %v items, got %v.\n", len(expected), len(gotten))
+	}
+
+	gotten, err = pipeline.Apply([]int{1, 2, 3})
+	if err != nil || !slices.Equal(gotten, []int{-1, -1, -1}) {
+		t.Errorf("TestTryMap(); unstopped run mismatch. Got: [%v, %v]\n", gotten, err)
+	}
+
+	errBadRow := errors.New("bad row")
+
+	var failing Pipeline[int]
+	failing.TryMap(func(index int, value int) (int, error) {
+		if value == 7 {
+			return 0, errBadRow
+		}
+		return value, nil
+	})
+
+	if _, err := failing.Apply(Range(10)); !errors.Is(err, errBadRow) || !strings.Contains(err.Error(), "element 7") {
+		t.Errorf("TestTryMap(); expected the wrapped error for element 7. Got: [%v]\n", err)
+	}
+}
+
+func TestDistinctLast(t *testing.T) {
+	type event struct {
+		Key   string
+		Value int
+	}
+
+	var pipeline Pipeline[event]
+	pipeline.DistinctLast(func(value event) string { return value.Key }, "newest per key")
+
+	gotten, err := pipeline.Apply([]event{{"a", 1}, {"b", 2}, {"a", 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []event{{"b", 2}, {"a", 3}}
+	if !slices.Equal(gotten, expected) {
+		t.Errorf("TestDistinctLast(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if err := pipeline.Validate(); err != nil {
+		t.Errorf("TestDistinctLast(); unexpected Validate() error: %v", err)
+	}
+}
+
+func TestWithName(t *testing.T) {
+	var pipeline Pipeline[int]
+	pipeline.WithName("ingest-cleanup")
+	pipeline.Take(1)
+
+	if out := pipeline.String(); !strings.HasPrefix(out, "Pipeline: ingest-cleanup\n") {
+		t.Errorf("TestWithName(); name missing from String().\nGot: [%v]\n", out)
+	}
+
+	if name := pipeline.Stats().Name; name != "ingest-cleanup" {
+		t.Errorf("TestWithName(); name missing from Stats().\nGot: [%v]\n", name)
+	}
+
+	var unnamed Pipeline[int]
+	if out := unnamed.String(); strings.Contains(out, "Pipeline:") {
+		t.Errorf("TestWithName(); unnamed pipeline should have no name line.\nGot: [%v]\n", out)
+	}
+}
+
+func TestUnclonable(t *testing.T) {
+	type guarded struct {
+		sync.Mutex
+		Value int
+	}
+	type counted struct {
+		Hits *atomic.Int64
+	}
+
+	var pipeline Pipeline[guarded]
+
+	if _, err := pipeline.Apply(make([]guarded, 3)); !errors.Is(err, ErrUnclonable) {
+		t.Errorf("TestUnclonable(); expected ErrUnclonable under the clone default. Got: [%v]\n", err)
+	}
+	if _, err := pipeline.Apply(make([]guarded, 3), Opt_InPlace); err != nil {
+		t.Errorf("TestUnclonable(); Opt_InPlace should not clone. Got: [%v]\n", err)
+	}
+
+	var pointers Pipeline[counted]
+	if _, err := pointers.Apply(make([]counted, 1), Opt_ClonePool); !errors.Is(err, ErrUnclonable) {
+		t.Errorf("TestUnclonable(); expected ErrUnclonable through a pointer. Got: [%v]\n", err)
+	}
+
+	pointers.CloneWith(slices.Clone[[]counted])
+	if _, err := pointers.Apply(make([]counted, 1)); err != nil {
+		t.Errorf("TestUnclonable(); a clone hook should be trusted. Got: [%v]\n", err)
+	}
+
+	type node struct {
+		Next *node
+		Tags map[string][]int
+	}
+	var plain Pipeline[node]
+	if _, err := plain.Apply(make([]node, 1)); err != nil {
+		t.Errorf("TestUnclonable(); recursive type without locks should clone. Got: [%v]\n", err)
+	}
+}
+
+func TestCloneWithSnapshots(t *testing.T) {
+	type guarded struct {
+		sync.Mutex
+		Value int
+	}
+
+	var calls int
+	var collected []guarded
+
+	var pipeline Pipeline[guarded]
+	pipeline.CloneWith(func(in []guarded) []guarded {
+		calls++
+		out := make([]guarded, len(in))
+		for idx := range in {
+			out[idx].Value = in[idx].Value
+		}
+		return out
+	})
+	pipeline.Collect(&collected)
+
+	input := make([]guarded, 3)
+	keyer := func([]guarded) string { return "guarded" }
+
+	if _, err := pipeline.ApplyCached(input, keyer); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 { // input, Collect() snapshot, cached copy
+		t.Errorf("TestCloneWithSnapshots(); hook skipped on a miss.\nExpected: [3] calls Got: [%v]\n", calls)
+	}
+	if len(collected) != len(input) {
+		t.Errorf("TestCloneWithSnapshots(); snapshot mismatch.\nExpected: [%v] items Got: [%v]\n", len(input), len(collected))
+	}
+
+	if _, err := pipeline.ApplyCached(input, keyer); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 4 {
+		t.Errorf("TestCloneWithSnapshots(); hook skipped on a hit.\nExpected: [4] calls Got: [%v]\n", calls)
+	}
+}
+
+func TestTryForeach(t *testing.T) {
+	errWrite := errors.New("write failed")
+
+	var visited []int
+	var serial Pipeline[int]
+	serial.TryForeach(func(value int) error {
+		if value == 5 {
+			return errWrite
+		}
+		visited = append(visited, value)
+		return nil
+	})
+
+	if _, err := serial.Apply(Range(10)); !errors.Is(err, errWrite) || !strings.Contains(err.Error(), "element 5") {
+		t.Errorf("TestTryForeach(); expected the wrapped error for element 5. Got: [%v]\n", err)
+	}
+	if !slices.Equal(visited, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("TestTryForeach(); serial run went past the failure. Got: [%v]\n", visited)
+	}
+
+	var calls atomic.Int64
+	var concurrent Pipeline[int]
+	concurrent.SetSerialThreshold(0)
+	concurrent.TryForeach(func(value int) error {
+		calls.Add(1)
+		if value == 100 {
+			return errWrite
+		}
+		time.Sleep(time.Microsecond)
+		return nil
+	})
+
+	input := Range(200_000)
+	if _, err := concurrent.Apply(input, Opt_CFE); !errors.Is(err, errWrite) {
+		t.Errorf("TestTryForeach(); expected errWrite under Opt_CFE. Got: [%v]\n", err)
+	}
+	if n := calls.Load(); n == int64(len(input)) {
+		t.Errorf("TestTryForeach(); workers were not cancelled; every element was visited.\n")
+	}
+
+	if _, err := concurrent.Apply([]int{1, 2, 3}, Opt_CFE); err != nil {
+		t.Errorf("TestTryForeach(); unexpected error without failures: %v", err)
+	}
+}
+
+func TestLazyClone(t *testing.T) {
+	input := make([]*int, 100)
+	for idx := range input {
+		input[idx] = new(int)
+		*input[idx] = idx
+	}
+
+	var cloned int
+	var pipeline Pipeline[*int]
+	pipeline.CloneWith(func(in []*int) []*int {
+		cloned += len(in)
+		return clone.Clone(in)
+	})
+	pipeline.Filter(func(value *int) bool { return *value%10 == 0 })
+	pipeline.Skip(1)
+	pipeline.Map(func(index int, value *int) *int {
+		*value = -*value
+		return value
+	})
+
+	gotten, err := pipeline.Apply(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cloned != 9 {
+		t.Errorf("TestLazyClone(); expected only the 9 survivors of the prefix to be cloned. Got: [%v]\n", cloned)
+	}
+	if len(gotten) != 9 || *gotten[0] != -10 || *gotten[8] != -90 {
+		t.Errorf("TestLazyClone(); value mismatch. Got %v items starting at [%v]\n", len(gotten), *gotten[0])
+	}
+	for idx, val := range input {
+		if *val != idx {
+			t.Errorf("TestLazyClone(); input pointee %v mutated. Got: [%v]\n", idx, *val)
+		}
+	}
+
+	var filterOnly Pipeline[int]
+	filterOnly.Filter(func(value int) bool { return value < 50 })
+
+	numbers := Range(100)
+	kept, err := filterOnly.Apply(numbers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept[0] = -1
+	if !slices.Equal(numbers, Range(100)) {
+		t.Errorf("TestLazyClone(); a filter-only result must not alias the input.\n")
+	}
+}
+
+func TestForeachAsync(t *testing.T) {
+	input := Range(100)
+
+	elapsed := func(concurrency int) (time.Duration, int64) {
+		var seen atomic.Int64
+		var pipeline Pipeline[int]
+		if err := pipeline.ForeachAsync(func(value int) {
+			time.Sleep(2 * time.Millisecond)
+			seen.Add(1)
+		}, concurrency); err != nil {
+			t.Fatal(err)
+		}
+
+		start := time.Now()
+		if _, err := pipeline.Apply(input); err != nil {
+			t.Fatal(err)
+		}
+		return time.Since(start), seen.Load()
+	}
+
+	serial, serialSeen := elapsed(1)
+	wide, wideSeen := elapsed(50)
+
+	if serialSeen != 100 || wideSeen != 100 {
+		t.Errorf("TestForeachAsync(); every element should be visited once. Got: [%v, %v]\n", serialSeen, wideSeen)
+	}
+	if wide*4 > serial {
+		t.Errorf("TestForeachAsync(); higher concurrency should cut wall time. Serial: [%v] Concurrent: [%v]\n", serial, wide)
+	}
+
+	var pipeline Pipeline[int]
+	if err := pipeline.ForeachAsync(func(value int) {}, 0); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("TestForeachAsync(); expected ErrInvalidCount. Got: [%v]\n", err)
+	}
+}
+
+func TestFilterNot(t *testing.T) {
+	isEven := func(value int) bool { return value%2 == 0 }
+
+	var pipeline Pipeline[int]
+	pipeline.FilterNot(isEven, "odds only")
+
+	gotten, err := pipeline.Apply([]int{1, 2, 3, 4, 5, 6, 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(gotten, []int{1, 3, 5, 7}) {
+		t.Errorf("TestFilterNot(); value mismatch.\nExpected: [[1 3 5 7]] Got: [%v]\n", gotten)
+	}
+}
+
+func TestFilterDensity(t *testing.T) {
+	input := benchmarkInput(100_000)
+
+	for _, keepPercent := range []int{0, 1, 50, 90, 100} {
+		keep := func(value int) bool { return value%100 < keepPercent }
+
+		var pipeline Pipeline[int]
+		pipeline.Filter(keep)
+
+		expected := slices.DeleteFunc(slices.Clone(input), func(value int) bool { return !keep(value) })
+
+		gotten, err := pipeline.Apply(input)
+		if err != nil || !slices.Equal(gotten, expected) {
+			t.Errorf("TestFilterDensity(); %v%% survivors mismatch. Got %v of %v items, err: [%v]\n", keepPercent, len(gotten), len(expected), err)
+		}
+	}
+}
+
+func benchmarkFilterDensity(b *testing.B, keepPercent int) {
+	var pipeline Pipeline[int]
+	pipeline.Filter(func(value int) bool { return value%100 < keepPercent })
+
+	input := benchmarkInput(1_000_000)
+	b.ReportAllocs()
+
+	for b.Loop() {
+		pipeline.Apply(input)
+	}
+}
+
+func BenchmarkFilterKeep1(b *testing.B)  { benchmarkFilterDensity(b, 1) }
+func BenchmarkFilterKeep90(b *testing.B) { benchmarkFilterDensity(b, 90) }
@@ -78,23 +78,9 @@ func main() {
 
 // return a slice of ints, incrementally valued, that takes up 'size' bytes
 func getList(size int) []int {
-	if size/int(unsafe.Sizeof(int(0))) < 1 {
-		return nil
-	}
-
-	numbers := make([]int, size/int(unsafe.Sizeof(int(0))))
-	var pipe derp.Pipeline[int]
-
-	pipe.Map(func(index int, value int) int {
-		return index + 1
-	}, "Map the same numbers every time for consistency")
-
-	numbers, err := pipe.Apply(numbers)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	return numbers
+	return derp.Generate(size/int(unsafe.Sizeof(int(0))), func(index int) int {
+		return index + 1 // the same numbers every time for consistency
+	})
 }
 
 func isPrime(value int) bool {
@@ -0,0 +1,686 @@
+package derp
+
+// Standalone adapters that operate on plain slices without building a Pipeline.
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// A generic two-value tuple.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Pair each element with its index, starting from 0.
+func Enumerate[T any](in []T) []Pair[int, T] {
+	return EnumerateFrom(in, 0)
+}
+
+// Pair each element with its index, counting up from start.
+func EnumerateFrom[T any](in []T, start int) []Pair[int, T] {
+	out := make([]Pair[int, T], len(in))
+
+	for idx, val := range in {
+		out[idx] = Pair[int, T]{First: start + idx, Second: val}
+	}
+
+	return out
+}
+
+// Return the n largest elements according to less, largest first.
+//
+// Each worker keeps a bounded min-heap of size n over its chunk and the heaps are merged at the end,
+// so the cost is O(len·log n) rather than the O(len·log len) of a full sort. Returns nil if n < 1.
+func TopN[T any](in []T, n int, less func(a, b T) bool) []T {
+	if n < 1 || len(in) == 0 {
+		return nil
+	}
+
+	numWorkers := runtime.GOMAXPROCS(0)
+	partials := make([]*boundedHeap[T], numWorkers)
+
+	parallelChunks(len(in), numWorkers, func(worker, start, end int) {
+		h := &boundedHeap[T]{items: make([]T, 0, min(n, end-start)), less: less}
+		for _, v := range in[start:end] {
+			h.offer(v, n)
+		}
+		partials[worker] = h
+	})
+
+	merged := &boundedHeap[T]{items: make([]T, 0, n), less: less}
+	for _, h := range partials {
+		if h == nil {
+			continue
+		}
+		for _, v := range h.items {
+			merged.offer(v, n)
+		}
+	}
+
+	// Popping a min-heap yields ascending order; fill from the back for largest first.
+	out := make([]T, merged.Len())
+	for idx := len(out) - 1; idx >= 0; idx-- {
+		out[idx] = heap.Pop(merged).(T)
+	}
+
+	return slices.Clip(out)
+}
+
+// Map each element with fn and keep the result only where fn reports true, in a single concurrent pass.
+// Output preserves input order.
+func FilterMap[T, U any](in []T, fn func(value T) (U, bool)) ([]U, error) {
+	if len(in) < 1 {
+		return nil, ErrEmptyInput
+	}
+
+	numWorkers := runtime.GOMAXPROCS(0)
+	results := make([][]U, numWorkers)
+
+	parallelChunks(len(in), numWorkers, func(worker, start, end int) {
+		out := make([]U, 0, end-start)
+		for _, v := range in[start:end] {
+			if mapped, ok := fn(v); ok {
+				out = append(out, mapped)
+			}
+		}
+		results[worker] = out
+	})
+
+	return slices.Concat(results...), nil
+}
+
+// Keep the elements pred accepts, along with each survivor's index in in, eg. to write updates back to the
+// source. Filtered concurrently by chunk; both slices preserve input order and line up with each other.
+func FilterWithIndex[T any](in []T, pred func(T) bool) ([]T, []int) {
+	numWorkers := runtime.GOMAXPROCS(0)
+	values := make([][]T, numWorkers)
+	indexes := make([][]int, numWorkers)
+
+	parallelChunks(len(in), numWorkers, func(worker, start, end int) {
+		for idx := start; idx < end; idx++ {
+			if pred(in[idx]) {
+				values[worker] = append(values[worker], in[idx])
+				indexes[worker] = append(indexes[worker], idx)
+			}
+		}
+	})
+
+	return slices.Concat(values...), slices.Concat(indexes...)
+}
+
+// One-off Map without building a Pipeline: return in with fn applied to each element, by concurrent chunks.
+// in is cloned first exactly as Apply() would, and options work the same, except the result is returned
+// even under Opt_InPlace. Empty input gives an empty slice.
+// Panics wherever Apply() would return an error: if fn is nil, if the options conflict, or if T holds a sync or
+// sync/atomic value and would be deep-cloned (ErrUnclonable; pass Opt_InPlace instead).
+func Map[T any](in []T, fn func(index int, value T) T, options ...Option) []T {
+	if fn == nil {
+		panic("derp: Map() called with a nil function")
+	}
+
+	var pipeline Pipeline[T]
+	pipeline.Map(fn)
+
+	return applyOnce(&pipeline, in, options, "Map")
+}
+
+// One-off Filter without building a Pipeline: return the elements pred accepts, in input order. Clones and
+// options as for Map(). Empty input gives an empty slice. Panics in the same cases as Map(), and also under
+// Opt_MapInPlace, which only admits Map orders.
+func Filter[T any](in []T, pred func(value T) bool, options ...Option) []T {
+	if pred == nil {
+		panic("derp: Filter() called with a nil function")
+	}
+
+	var pipeline Pipeline[T]
+	pipeline.Filter(pred)
+
+	return applyOnce(&pipeline, in, options, "Filter")
+}
+
+// Run a one-order pipeline for Map() and Filter(), turning any Apply() error into a panic.
+func applyOnce[T any](pipeline *Pipeline[T], in []T, options []Option, caller string) []T {
+	if len(in) == 0 {
+		return []T{}
+	}
+
+	out, err := pipeline.apply(in, options)
+	if err != nil {
+		panic(fmt.Sprintf("derp: %v(): %v", caller, err))
+	}
+
+	return out
+}
+
+// Bucket elements by key.
+//
+// Each worker buckets its own chunk, then the partial buckets are appended chunk by chunk. Chunks are
+// contiguous and merged in input order, so every group holds its elements in their original relative
+// order, whatever the worker count or scheduling.
+func GroupBy[T any, K comparable](in []T, key func(T) K) map[K][]T {
+	numWorkers := runtime.GOMAXPROCS(0)
+	partials := make([]map[K][]T, numWorkers)
+
+	parallelChunks(len(in), numWorkers, func(worker, start, end int) {
+		partial := make(map[K][]T)
+		for _, v := range in[start:end] {
+			k := key(v)
+			partial[k] = append(partial[k], v)
+		}
+		partials[worker] = partial
+	})
+
+	out := make(map[K][]T)
+	for _, partial := range partials {
+		for k, group := range partial {
+			out[k] = append(out[k], group...)
+		}
+	}
+
+	return out
+}
+
+// Bucket elements by key and fold each bucket to a single value, like SQL's GROUP BY ... SUM.
+//
+// Each worker folds its own chunk into a partial map and the partials are then merged per key, so reduce
+// must be associative (eg. sum, min, max) for the result to match a serial fold. Chunks are merged
+// in input order, so reduce needn't be commutative.
+func GroupByReduce[T any, K comparable](in []T, key func(T) K, reduce func(acc, v T) T) map[K]T {
+	numWorkers := runtime.GOMAXPROCS(0)
+	partials := make([]map[K]T, numWorkers)
+
+	parallelChunks(len(in), numWorkers, func(worker, start, end int) {
+		partial := make(map[K]T)
+		for _, v := range in[start:end] {
+			k := key(v)
+			if acc, ok := partial[k]; ok {
+				partial[k] = reduce(acc, v)
+			} else {
+				partial[k] = v
+			}
+		}
+		partials[worker] = partial
+	})
+
+	out := make(map[K]T)
+	for _, partial := range partials {
+		for k, v := range partial {
+			if acc, ok := out[k]; ok {
+				out[k] = reduce(acc, v)
+			} else {
+				out[k] = v
+			}
+		}
+	}
+
+	return out
+}
+
+// Like GroupByReduce, but return the groups as (key, result) pairs sorted by key, ie. GROUP BY key ORDER BY key.
+func GroupByReduceSorted[T any, K cmp.Ordered](in []T, key func(T) K, reduce func(acc, v T) T) []Pair[K, T] {
+	groups := GroupByReduce(in, key, reduce)
+
+	out := make([]Pair[K, T], 0, len(groups))
+	for _, k := range slices.Sorted(maps.Keys(groups)) {
+		out = append(out, Pair[K, T]{First: k, Second: groups[k]})
+	}
+
+	return out
+}
+
+// Return the element with the largest key, and false for empty input. Ties go to the first seen.
+func MaxBy[T any, K cmp.Ordered](in []T, key func(T) K) (T, bool) {
+	return bestBy(in, key, func(a, b K) bool { return a > b })
+}
+
+// Return the element with the smallest key, and false for empty input. Ties go to the first seen.
+func MinBy[T any, K cmp.Ordered](in []T, key func(T) K) (T, bool) {
+	return bestBy(in, key, func(a, b K) bool { return a < b })
+}
+
+// Pick the best element per chunk, then the best of those. better must be strict so ties keep the earliest.
+func bestBy[T any, K cmp.Ordered](in []T, key func(T) K, better func(a, b K) bool) (T, bool) {
+	if len(in) == 0 {
+		var zero T
+		return zero, false
+	}
+
+	numWorkers := runtime.GOMAXPROCS(0)
+	bests := make([]Pair[K, T], numWorkers)
+	found := make([]bool, numWorkers)
+
+	parallelChunks(len(in), numWorkers, func(worker, start, end int) {
+		best := Pair[K, T]{key(in[start]), in[start]}
+		for _, v := range in[start+1 : end] {
+			if k := key(v); better(k, best.First) {
+				best = Pair[K, T]{k, v}
+			}
+		}
+		bests[worker] = best
+		found[worker] = true
+	})
+
+	best := bests[0]
+	for idx, candidate := range bests[1:] {
+		if found[idx+1] && better(candidate.First, best.First) {
+			best = candidate
+		}
+	}
+
+	return best.Second, true
+}
+
+// Concatenate in with sep between elements. The output is sized up front from the summed lengths.
+func Join(in []string, sep string) string {
+	if len(in) == 0 {
+		return ""
+	}
+
+	size := len(sep) * (len(in) - 1)
+	for _, s := range in {
+		size += len(s)
+	}
+
+	var out strings.Builder
+	out.Grow(size)
+
+	out.WriteString(in[0])
+	for _, s := range in[1:] {
+		out.WriteString(sep)
+		out.WriteString(s)
+	}
+
+	return out.String()
+}
+
+// Byte-slice counterpart of Join. Always returns a new slice.
+func JoinBytes(in [][]byte, sep []byte) []byte {
+	if len(in) == 0 {
+		return []byte{}
+	}
+
+	size := len(sep) * (len(in) - 1)
+	for _, b := range in {
+		size += len(b)
+	}
+
+	out := make([]byte, 0, size)
+
+	out = append(out, in[0]...)
+	for _, b := range in[1:] {
+		out = append(out, sep...)
+		out = append(out, b...)
+	}
+
+	return out
+}
+
+// Return the index of the largest element according to less, and false for empty input.
+// Ties go to the lowest index.
+func ArgMax[T any](in []T, less func(a, b T) bool) (int, bool) {
+	return argBest(in, func(a, b T) bool { return less(b, a) })
+}
+
+// Return the index of the smallest element according to less, and false for empty input.
+// Ties go to the lowest index.
+func ArgMin[T any](in []T, less func(a, b T) bool) (int, bool) {
+	return argBest(in, less)
+}
+
+// Track the best index per chunk, then the best of those. better must be strict so ties keep the lowest index.
+func argBest[T any](in []T, better func(a, b T) bool) (int, bool) {
+	if len(in) == 0 {
+		return -1, false
+	}
+
+	numWorkers := runtime.GOMAXPROCS(0)
+	bests := make([]int, numWorkers)
+	for idx := range bests {
+		bests[idx] = -1
+	}
+
+	parallelChunks(len(in), numWorkers, func(worker, start, end int) {
+		best := start
+		for idx := start + 1; idx < end; idx++ {
+			if better(in[idx], in[best]) {
+				best = idx
+			}
+		}
+		bests[worker] = best
+	})
+
+	best := bests[0]
+	for _, candidate := range bests[1:] {
+		if candidate >= 0 && better(in[candidate], in[best]) {
+			best = candidate
+		}
+	}
+
+	return best, true
+}
+
+// Return a copy of in with every value bounded to [lo, hi], computed concurrently by chunk.
+// Requires lo <= hi.
+func Clamp[T cmp.Ordered](in []T, lo, hi T) ([]T, error) {
+	clamp, err := ClampMap(lo, hi)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]T, len(in))
+
+	parallelChunks(len(in), runtime.GOMAXPROCS(0), func(_, start, end int) {
+		for idx := start; idx < end; idx++ {
+			out[idx] = clamp(idx, in[idx])
+		}
+	})
+
+	return out, nil
+}
+
+// Build a Map() function that bounds each value to [lo, hi], eg. pipeline.Map(clamp, "sanitize").
+// Requires lo <= hi.
+func ClampMap[T cmp.Ordered](lo, hi T) (func(index int, value T) T, error) {
+	if hi < lo {
+		return nil, fmt.Errorf("Clamp(): lo %v is greater than hi %v", lo, hi)
+	}
+
+	return func(_ int, value T) T {
+		return min(max(value, lo), hi)
+	}, nil
+}
+
+// Build a slice of n values where out[idx] = gen(idx), filled concurrently by chunk.
+// Useful as pipeline input when the data is generated rather than read. n below 1 yields nil.
+// Panics if gen is nil.
+func Generate[T any](n int, gen func(index int) T) []T {
+	if gen == nil {
+		panic("derp: Generate() called with a nil function")
+	}
+
+	if n < 1 {
+		return nil
+	}
+
+	out := make([]T, n)
+
+	parallelChunks(n, runtime.GOMAXPROCS(0), func(_, start, end int) {
+		for idx := start; idx < end; idx++ {
+			out[idx] = gen(idx)
+		}
+	})
+
+	return out
+}
+
+// The ints [0, n). n below 1 yields nil.
+func Range(n int) []int {
+	return Generate(n, func(index int) int {
+		return index
+	})
+}
+
+// Apply fn to every size-long window of in, advancing step items at a time, and return one result per window.
+// Windows are processed concurrently by chunk and share in's backing array, so fn must not modify them.
+// A trailing partial window is dropped; if in is shorter than size there are no windows.
+// Requires size and step >= 1.
+func MapWindows[T, R any](in []T, size, step int, fn func(window []T) R) ([]R, error) {
+	if size < 1 || step < 1 {
+		return nil, fmt.Errorf("MapWindows(%v, %v): %w", size, step, ErrInvalidCount)
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("MapWindows(): %w", ErrNilFunc)
+	}
+
+	if len(in) < size {
+		return nil, nil
+	}
+
+	out := make([]R, (len(in)-size)/step+1)
+
+	parallelChunks(len(out), runtime.GOMAXPROCS(0), func(_, start, end int) {
+		for idx := start; idx < end; idx++ {
+			lo := idx * step
+			out[idx] = fn(in[lo : lo+size : lo+size])
+		}
+	})
+
+	return out, nil
+}
+
+// Concatenate chunks, eg. from ApplyChunked(), back into a single slice.
+func Flatten[T any](chunks [][]T) []T {
+	return slices.Concat(chunks...)
+}
+
+// Return the running totals of in: out[i] = in[0] op in[1] op ... op in[i], eg. cumulative sums with +.
+//
+// Runs in three passes: each worker totals its own chunk in place, the per-chunk totals are combined in order,
+// then every chunk after the first folds in the total of the chunks before it. op must be associative, though
+// not commutative, and identity must leave any value unchanged (0 for +, 1 for *, "" for string concat);
+// otherwise the result differs from a serial running total. op is called about twice per element.
+func PrefixScan[T any](in []T, identity T, op func(a, b T) T) []T {
+	out := make([]T, len(in))
+	if len(in) == 0 {
+		return out
+	}
+
+	numWorkers := runtime.GOMAXPROCS(0)
+	totals := make([]T, numWorkers)
+	filled := make([]bool, numWorkers) // trailing workers get no chunk when in is short
+
+	parallelChunks(len(in), numWorkers, func(worker, start, end int) {
+		acc := in[start]
+		out[start] = acc
+		for idx := start + 1; idx < end; idx++ {
+			acc = op(acc, in[idx])
+			out[idx] = acc
+		}
+		totals[worker], filled[worker] = acc, true
+	})
+
+	// offsets[w] is everything before chunk w
+	offsets := make([]T, numWorkers)
+	acc := identity
+	for worker, total := range totals {
+		offsets[worker] = acc
+		if filled[worker] {
+			acc = op(acc, total)
+		}
+	}
+
+	parallelChunks(len(in), numWorkers, func(worker, start, end int) {
+		if worker == 0 {
+			return
+		}
+		for idx := start; idx < end; idx++ {
+			out[idx] = op(offsets[worker], out[idx])
+		}
+	})
+
+	return out
+}
+
+// A value repeated Count times in a row.
+type Run[T any] struct {
+	Value T
+	Count int
+}
+
+// Coalesce equal adjacent elements into runs in a single sequential pass.
+func RunLengthEncode[T comparable](in []T) []Run[T] {
+	var out []Run[T]
+
+	for _, val := range in {
+		if len(out) > 0 && out[len(out)-1].Value == val {
+			out[len(out)-1].Count++
+			continue
+		}
+		out = append(out, Run[T]{Value: val, Count: 1})
+	}
+
+	return out
+}
+
+// Expand runs back into a flat slice. Runs with a Count below 1 contribute nothing.
+func RunLengthDecode[T any](runs []Run[T]) []T {
+	size := 0
+	for _, run := range runs {
+		size += max(run.Count, 0)
+	}
+
+	out := make([]T, 0, size)
+	for _, run := range runs {
+		for range run.Count {
+			out = append(out, run.Value)
+		}
+	}
+
+	return out
+}
+
+// Split in into consecutive sub-slices, starting a new one wherever isBoundary(prev, cur) is true.
+// The first element always opens the first chunk. Chunks share in's backing array.
+func ChunkBy[T any](in []T, isBoundary func(prev, cur T) bool) [][]T {
+	if len(in) == 0 {
+		return nil
+	}
+
+	var out [][]T
+	start := 0
+
+	for idx := 1; idx < len(in); idx++ {
+		if isBoundary(in[idx-1], in[idx]) {
+			out = append(out, in[start:idx:idx])
+			start = idx
+		}
+	}
+
+	return append(out, in[start:])
+}
+
+// Swap rows and columns of a row-major matrix. Rows of unequal length are an error; see TransposePad.
+func Transpose[T any](in [][]T) ([][]T, error) {
+	for idx, row := range in {
+		if len(row) != len(in[0]) {
+			return nil, fmt.Errorf("Transpose(): row %v has %v columns, row 0 has %v", idx, len(row), len(in[0]))
+		}
+	}
+
+	var zero T
+	return TransposePad(in, zero), nil
+}
+
+// Like Transpose, but ragged input is padded with pad up to the longest row first.
+func TransposePad[T any](in [][]T, pad T) [][]T {
+	cols := 0
+	for _, row := range in {
+		cols = max(cols, len(row))
+	}
+
+	out := make([][]T, cols)
+	for c := range out {
+		out[c] = make([]T, len(in))
+		for r, row := range in {
+			if c < len(row) {
+				out[c][r] = row[c]
+			} else {
+				out[c][r] = pad
+			}
+		}
+	}
+
+	return out
+}
+
+// min-heap capped at a fixed size; the root is the smallest retained element.
+type boundedHeap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *boundedHeap[T]) Len() int           { return len(h.items) }
+func (h *boundedHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *boundedHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *boundedHeap[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+
+func (h *boundedHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// Keep v if the heap has room or v beats the current smallest.
+func (h *boundedHeap[T]) offer(v T, n int) {
+	if len(h.items) < n {
+		heap.Push(h, v)
+		return
+	}
+
+	if h.less(h.items[0], v) {
+		h.items[0] = v
+		heap.Fix(h, 0)
+	}
+}
+
+// Split [0, length) into contiguous chunks, one per worker, and run fn on each concurrently.
+// Blocks until every chunk is done. The worker count is clamped to [1, length], so no worker gets an
+// empty chunk, and a single worker runs inline without spawning a goroutine.
+func parallelChunks(length, numWorkers int, fn func(worker, start, end int)) {
+	spreadChunks(length, numWorkers, fn, func(task func()) { go task() })
+}
+
+// parallelChunks() with the goroutine start left to spawn, eg. to hand chunks to a WorkerPool.
+func spreadChunks(length, numWorkers int, fn func(worker, start, end int), spawn func(task func())) {
+	numWorkers = max(min(numWorkers, length), 1)
+
+	if numWorkers == 1 {
+		if length > 0 {
+			fn(0, 0, length)
+		}
+		return
+	}
+
+	chunkSize := (length + numWorkers - 1) / numWorkers
+
+	var wg sync.WaitGroup
+
+	for w := range numWorkers {
+		start := w * chunkSize
+
+		if start >= length {
+			break
+		}
+
+		end := min(start+chunkSize, length)
+
+		wg.Add(1)
+		spawn(func() {
+			defer wg.Done()
+			fn(w, start, end)
+		})
+	}
+
+	wg.Wait()
+}
+
+// Cut in into the same consecutive chunks parallelChunks() would hand to numWorkers workers.
+func splitChunks[T any](in []T, numWorkers int) [][]T {
+	numWorkers = max(min(numWorkers, len(in)), 1)
+	chunkSize := (len(in) + numWorkers - 1) / numWorkers
+
+	out := make([][]T, 0, numWorkers)
+	for start := 0; start < len(in); start += chunkSize {
+		out = append(out, in[start:min(start+chunkSize, len(in))])
+	}
+
+	return out
+}
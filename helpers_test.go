@@ -0,0 +1,410 @@
+package derp
+
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEnumerate(t *testing.T) {
+	expected := []Pair[int, string]{{0, "a"}, {1, "b"}}
+	gotten := Enumerate([]string{"a", "b"})
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestEnumerate(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	expected = []Pair[int, string]{{5, "a"}, {6, "b"}}
+	gotten = EnumerateFrom([]string{"a", "b"}, 5)
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestEnumerate(); offset value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+}
+
+func TestTopN(t *testing.T) {
+	numbers := []int{5, 1, 9, 3, 7, 2, 8, 6, 4, 10}
+
+	expected := []int{10, 9, 8}
+	gotten := TopN(numbers, 3, func(a, b int) bool { return a < b })
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestTopN(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if gotten := TopN(numbers, 20, func(a, b int) bool { return a < b }); len(gotten) != len(numbers) {
+		t.Errorf("TestTopN(); length mismatch when n > len.\nExpected: [%v] Got: [%v]\n", len(numbers), len(gotten))
+	}
+
+	if gotten := TopN(numbers, 0, func(a, b int) bool { return a < b }); gotten != nil {
+		t.Errorf("TestTopN(); expected nil for n < 1. Got: [%v]\n", gotten)
+	}
+}
+
+func benchmarkInput(size int) []int {
+	rng := rand.New(rand.NewPCG(1, 2))
+	numbers := make([]int, size)
+	for idx := range numbers {
+		numbers[idx] = rng.Int()
+	}
+	return numbers
+}
+
+func BenchmarkTopN(b *testing.B) {
+	numbers := benchmarkInput(1_000_000)
+
+	for b.Loop() {
+		TopN(numbers, 10, func(a, b int) bool { return a < b })
+	}
+}
+
+func BenchmarkSortTake(b *testing.B) {
+	numbers := benchmarkInput(1_000_000)
+
+	for b.Loop() {
+		sorted := slices.Clone(numbers)
+		slices.SortFunc(sorted, func(a, b int) int { return cmp.Compare(b, a) })
+		_ = sorted[:10]
+	}
+}
+
+func TestFilterWithIndex(t *testing.T) {
+	values, indexes := FilterWithIndex([]int{10, 11, 12, 13}, func(value int) bool { return value%2 == 0 })
+
+	if !slices.Equal(values, []int{10, 12}) || !slices.Equal(indexes, []int{0, 2}) {
+		t.Errorf("TestFilterWithIndex(); value mismatch.\nExpected: [[10 12] [0 2]] Got: [%v %v]\n", values, indexes)
+	}
+
+	input := benchmarkInput(100_000)
+	values, indexes = FilterWithIndex(input, func(value int) bool { return value%3 == 0 })
+
+	for pos, idx := range indexes {
+		if input[idx] != values[pos] || (pos > 0 && idx <= indexes[pos-1]) {
+			t.Fatalf("TestFilterWithIndex(); survivor %v doesn't match input index %v.\n", pos, idx)
+		}
+	}
+}
+
+func TestFilterMap(t *testing.T) {
+	expected := []int{1, 3}
+	gotten, err := FilterMap([]string{"1", "x", "3"}, func(value string) (int, bool) {
+		parsed, err := strconv.Atoi(value)
+		return parsed, err == nil
+	})
+
+	if err != nil {
+		t.Errorf("TestFilterMap(); error from FilterMap(): %v", err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestFilterMap(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if _, err := FilterMap([]string{}, func(value string) (int, bool) { return 0, true }); err == nil {
+		t.Errorf("TestFilterMap(); expected error for empty input")
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	input := Range(100_000)
+
+	groups := GroupBy(input, func(value int) int {
+		return (value * 7919) % 13
+	})
+
+	var total int
+	for key, group := range groups {
+		total += len(group)
+		if !slices.IsSorted(group) {
+			t.Errorf("TestGroupBy(); group %v out of input order.\n", key)
+		}
+	}
+
+	if len(groups) != 13 || total != len(input) {
+		t.Errorf("TestGroupBy(); size mismatch.\nExpected: [13 %v] Got: [%v %v]\n", len(input), len(groups), total)
+	}
+}
+
+func TestGroupByReduce(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	gotten := GroupByReduce(numbers, func(value int) string {
+		if value%2 == 0 {
+			return "even"
+		}
+		return "odd"
+	}, func(acc, value int) int {
+		return acc + value
+	})
+
+	expected := map[string]int{"even": 30, "odd": 25}
+
+	if !maps.Equal(expected, gotten) {
+		t.Errorf("TestGroupByReduce(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+}
+
+func TestGroupByReduceSorted(t *testing.T) {
+	gotten := GroupByReduceSorted(Range(10), func(value int) int {
+		return value % 3
+	}, func(acc, value int) int {
+		return acc + value
+	})
+
+	expected := []Pair[int, int]{{0, 18}, {1, 12}, {2, 15}}
+	if !slices.Equal(gotten, expected) {
+		t.Errorf("TestGroupByReduceSorted(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+}
+
+func TestMaxByMinBy(t *testing.T) {
+	type player struct {
+		name  string
+		score int
+	}
+
+	players := []player{{"a", 3}, {"b", 9}, {"c", 1}, {"d", 9}, {"e", 1}}
+	score := func(p player) int { return p.score }
+
+	if best, ok := MaxBy(players, score); !ok || best.name != "b" {
+		t.Errorf("TestMaxByMinBy(); MaxBy mismatch.\nExpected: [b] Got: [%v]\n", best)
+	}
+
+	if worst, ok := MinBy(players, score); !ok || worst.name != "c" {
+		t.Errorf("TestMaxByMinBy(); MinBy mismatch.\nExpected: [c] Got: [%v]\n", worst)
+	}
+
+	if _, ok := MaxBy([]player{}, score); ok {
+		t.Errorf("TestMaxByMinBy(); expected ok == false for empty input")
+	}
+}
+
+func TestJoin(t *testing.T) {
+	if gotten := Join([]string{"a", "bb", "ccc"}, ", "); gotten != "a, bb, ccc" {
+		t.Errorf("TestJoin(); value mismatch.\nExpected: [a, bb, ccc] Got: [%v]\n", gotten)
+	}
+
+	if gotten := Join(nil, ", "); gotten != "" {
+		t.Errorf("TestJoin(); expected empty string. Got: [%v]\n", gotten)
+	}
+
+	if gotten := JoinBytes([][]byte{[]byte("a"), []byte("bb")}, []byte("--")); string(gotten) != "a--bb" {
+		t.Errorf("TestJoin(); bytes value mismatch.\nExpected: [a--bb] Got: [%s]\n", gotten)
+	}
+}
+
+func TestArgMaxArgMin(t *testing.T) {
+	numbers := []int{3, 1, 4, 1, 5}
+	less := func(a, b int) bool { return a < b }
+
+	if idx, ok := ArgMax(numbers, less); !ok || idx != 4 {
+		t.Errorf("TestArgMaxArgMin(); ArgMax mismatch.\nExpected: [4] Got: [%v]\n", idx)
+	}
+
+	if idx, ok := ArgMin(numbers, less); !ok || idx != 1 {
+		t.Errorf("TestArgMaxArgMin(); ArgMin tie should resolve to lowest index.\nExpected: [1] Got: [%v]\n", idx)
+	}
+
+	if _, ok := ArgMax([]int{}, less); ok {
+		t.Errorf("TestArgMaxArgMin(); expected ok == false for empty input")
+	}
+}
+
+func TestClamp(t *testing.T) {
+	numbers := []int{-5, 0, 5, 15}
+
+	expected := []int{0, 0, 5, 10}
+	gotten, err := Clamp(numbers, 0, 10)
+	if err != nil {
+		t.Errorf("TestClamp(); error from Clamp(): %v", err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestClamp(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	clamp, err := ClampMap(0, 10)
+	if err != nil {
+		t.Fatalf("TestClamp(); error from ClampMap(): %v", err)
+	}
+
+	var pipe Pipeline[int]
+	pipe.Map(clamp)
+
+	if gotten, _ := pipe.Apply(numbers); !slices.Equal(expected, gotten) {
+		t.Errorf("TestClamp(); pipeline value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if _, err := Clamp(numbers, 10, 0); err == nil {
+		t.Errorf("TestClamp(); expected error for lo > hi")
+	}
+}
+
+func serialScan[T any](in []T, op func(a, b T) T) []T {
+	out := make([]T, len(in))
+	for idx, val := range in {
+		if idx == 0 {
+			out[idx] = val
+		} else {
+			out[idx] = op(out[idx-1], val)
+		}
+	}
+	return out
+}
+
+func TestPrefixScan(t *testing.T) {
+	add := func(a, b int) int { return a + b }
+
+	for _, size := range []int{0, 1, 3, 7, 1_000_003} {
+		input := benchmarkInput(size)
+		for idx := range input {
+			input[idx] %= 1000
+		}
+
+		if gotten, expected := PrefixScan(input, 0, add), serialScan(input, add); !slices.Equal(gotten, expected) {
+			t.Errorf("TestPrefixScan(); sum mismatch for %v items.\n", size)
+		}
+	}
+
+	// associative but not commutative
+	letters := strings.Split("derp is a reusable pipeline", "")
+	concat := func(a, b string) string { return a + b }
+
+	gotten := PrefixScan(letters, "", concat)
+	if last := gotten[len(gotten)-1]; last != "derp is a reusable pipeline" || !slices.Equal(gotten, serialScan(letters, concat)) {
+		t.Errorf("TestPrefixScan(); concat mismatch.\nExpected: [derp is a reusable pipeline] Got: [%v]\n", last)
+	}
+}
+
+func BenchmarkSerialScan(b *testing.B) {
+	input := benchmarkInput(1_000_000)
+	for b.Loop() {
+		serialScan(input, func(a, b int) int { return a + b })
+	}
+}
+
+func BenchmarkPrefixScan(b *testing.B) {
+	input := benchmarkInput(1_000_000)
+	for b.Loop() {
+		PrefixScan(input, 0, func(a, b int) int { return a + b })
+	}
+}
+
+func TestRunLength(t *testing.T) {
+	letters := []string{"a", "a", "b", "c", "c", "c", "a"}
+
+	expected := []Run[string]{{"a", 2}, {"b", 1}, {"c", 3}, {"a", 1}}
+	encoded := RunLengthEncode(letters)
+
+	if !slices.Equal(expected, encoded) {
+		t.Errorf("TestRunLength(); encode mismatch.\nExpected: [%v] Got: [%v]\n", expected, encoded)
+	}
+
+	if decoded := RunLengthDecode(encoded); !slices.Equal(letters, decoded) {
+		t.Errorf("TestRunLength(); round trip mismatch.\nExpected: [%v] Got: [%v]\n", letters, decoded)
+	}
+}
+
+func TestChunkBy(t *testing.T) {
+	lines := []string{"BEGIN", "a", "b", "BEGIN", "c", "BEGIN"}
+
+	expected := [][]string{{"BEGIN", "a", "b"}, {"BEGIN", "c"}, {"BEGIN"}}
+	gotten := ChunkBy(lines, func(_, cur string) bool {
+		return cur == "BEGIN"
+	})
+
+	if len(expected) != len(gotten) {
+		t.Fatalf("TestChunkBy(); chunk count mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	for idx, val := range expected {
+		if !slices.Equal(val, gotten[idx]) {
+			t.Errorf("TestChunkBy(); chunk %v mismatch.\nExpected: [%v] Got: [%v]\n", idx, val, gotten[idx])
+		}
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	matrix := [][]int{{1, 2, 3}, {4, 5, 6}}
+
+	expected := [][]int{{1, 4}, {2, 5}, {3, 6}}
+	gotten, err := Transpose(matrix)
+	if err != nil {
+		t.Errorf("TestTranspose(); error from Transpose(): %v", err)
+	}
+
+	if !slices.EqualFunc(expected, gotten, slices.Equal) {
+		t.Errorf("TestTranspose(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	ragged := [][]int{{1, 2, 3}, {4}}
+
+	if _, err := Transpose(ragged); err == nil {
+		t.Errorf("TestTranspose(); expected error for ragged input")
+	}
+
+	expected = [][]int{{1, 4}, {2, -1}, {3, -1}}
+	if gotten := TransposePad(ragged, -1); !slices.EqualFunc(expected, gotten, slices.Equal) {
+		t.Errorf("TestTranspose(); padded value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	gotten := Generate(6, func(index int) int {
+		return index * index
+	})
+
+	expected := []int{0, 1, 4, 9, 16, 25}
+	if !slices.Equal(gotten, expected) {
+		t.Errorf("TestGenerate(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if gotten := Range(5); !slices.Equal(gotten, []int{0, 1, 2, 3, 4}) {
+		t.Errorf("TestGenerate(); Range value mismatch.\nExpected: [[0 1 2 3 4]] Got: [%v]\n", gotten)
+	}
+
+	if gotten := Range(0); gotten != nil {
+		t.Errorf("TestGenerate(); expected nil for n = 0. Got: [%v]\n", gotten)
+	}
+}
+
+func TestMapWindows(t *testing.T) {
+	mean := func(window []float64) float64 {
+		total := 0.0
+		for _, v := range window {
+			total += v
+		}
+		return total / float64(len(window))
+	}
+
+	input := Generate(10, func(index int) float64 { return float64(index + 1) })
+
+	gotten, err := MapWindows(input, 3, 1, mean)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []float64{2, 3, 4, 5, 6, 7, 8, 9}
+	if !slices.Equal(gotten, expected) {
+		t.Errorf("TestMapWindows(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	gotten, err = MapWindows(input, 4, 3, mean)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = []float64{2.5, 5.5, 8.5}
+	if !slices.Equal(gotten, expected) {
+		t.Errorf("TestMapWindows(); stepped value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if _, err := MapWindows(input, 0, 1, mean); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("TestMapWindows(); expected ErrInvalidCount. Got: [%v]\n", err)
+	}
+}
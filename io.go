@@ -0,0 +1,265 @@
+package derp
+
+// Adapters for getting data into and out of pipelines.
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+)
+
+// Longest line FromLines() and Lines() accept; bufio.Scanner's default is only 64KiB.
+const maxLineLength = 64 * 1024 * 1024
+
+// Unmarshal each JSON document in in, concurrently by chunk, into a []T.
+//
+// Returns the first decode error in input order. With Opt_CollectErrors, every decode error is joined
+// instead. Power options throttle the workers as in Apply().
+func DecodeJSON[T any](in [][]byte, options ...Option) ([]T, error) {
+	if len(in) < 1 {
+		return nil, ErrEmptyInput
+	}
+
+	collect := slices.Contains(options, Opt_CollectErrors)
+	options = slices.DeleteFunc(slices.Clone(options), func(opt Option) bool {
+		return opt == Opt_CollectErrors
+	})
+
+	if err := checkOptions(options); err != nil {
+		return nil, err
+	}
+
+	out := make([]T, len(in))
+	errs := make([]error, len(in))
+
+	parallelChunks(len(in), workerCount(options), func(_, start, end int) {
+		for idx := start; idx < end; idx++ {
+			if err := json.Unmarshal(in[idx], &out[idx]); err != nil {
+				errs[idx] = fmt.Errorf("DecodeJSON(): element %v: %w", idx, err)
+			}
+		}
+	})
+
+	if collect {
+		if err := errors.Join(errs...); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// Write in to w as newline-delimited JSON, one element per line, in input order.
+//
+// Elements are marshalled concurrently by chunk, then written serially through a buffered writer.
+// Returns the first marshal error in input order before anything is written, or the first write error.
+// Power options throttle the workers as in Apply().
+func EncodeJSONTo[T any](in []T, w io.Writer, options ...Option) error {
+	if err := checkOptions(options); err != nil {
+		return err
+	}
+
+	encoded := make([][]byte, len(in))
+	errs := make([]error, len(in))
+
+	parallelChunks(len(in), workerCount(options), func(_, start, end int) {
+		for idx := start; idx < end; idx++ {
+			encoded[idx], errs[idx] = json.Marshal(in[idx])
+		}
+	})
+
+	for idx, err := range errs {
+		if err != nil {
+			return fmt.Errorf("EncodeJSONTo(): element %v: %w", idx, err)
+		}
+	}
+
+	buffered := bufio.NewWriter(w)
+
+	for _, line := range encoded {
+		if _, err := buffered.Write(line); err != nil {
+			return err
+		}
+		if err := buffered.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+
+	return buffered.Flush()
+}
+
+// Read r line by line into a slice ready to feed a Pipeline[string]. Line endings are stripped.
+// Lines up to 64MiB long are supported; anything longer, or a read error, is returned as an error.
+func FromLines(r io.Reader) ([]string, error) {
+	var out []string
+
+	for line, err := range Lines(r) {
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, line)
+	}
+
+	return out, nil
+}
+
+// Streaming form of FromLines(). Yields each line with a nil error; if scanning fails, the final pair
+// carries the error instead.
+func Lines(r io.Reader) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, maxLineLength)
+
+		for scanner.Scan() {
+			if !yield(scanner.Text(), nil) {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield("", fmt.Errorf("Lines(): %w", err))
+		}
+	}
+}
+
+// encoding/csv reader settings. The zero value reads standard comma-separated records.
+type CSVOptions struct {
+	Comma            rune // field delimiter; ',' if zero
+	Comment          rune // lines starting with it are ignored; none if zero
+	FieldsPerRecord  int  // as csv.Reader: 0 means match the first record, negative means any
+	LazyQuotes       bool
+	TrimLeadingSpace bool
+}
+
+func (opts CSVOptions) reader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+	reader.Comment = opts.Comment
+	reader.FieldsPerRecord = opts.FieldsPerRecord
+	reader.LazyQuotes = opts.LazyQuotes
+	reader.TrimLeadingSpace = opts.TrimLeadingSpace
+
+	return reader
+}
+
+// Read every CSV record from r. Parse errors carry the offending line number.
+func FromCSV(r io.Reader) ([][]string, error) {
+	return FromCSVWith(r, CSVOptions{})
+}
+
+// Like FromCSV, with custom reader settings.
+func FromCSVWith(r io.Reader, opts CSVOptions) ([][]string, error) {
+	return FromCSVFunc(r, opts, func(record []string) ([]string, error) {
+		return record, nil
+	})
+}
+
+// Read CSV records from r and convert each into a T with fn, so a pipeline can work on typed rows.
+// Errors from parsing or from fn are returned with the line number the record started on.
+func FromCSVFunc[T any](r io.Reader, opts CSVOptions, fn func(record []string) (T, error)) ([]T, error) {
+	reader := opts.reader(r)
+
+	var out []T
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("FromCSV(): %w", err)
+		}
+
+		row, err := fn(record)
+		if err != nil {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("FromCSV(): record on line %v: %w", line, err)
+		}
+
+		out = append(out, row)
+	}
+}
+
+// Run the pipeline, then write format(v) for each resulting element to w through a buffered writer.
+// Returns the number of bytes that reached w. The first error from w aborts the write and is returned.
+func (pipeline *Pipeline[T]) WriteTo(input []T, w io.Writer, format func(T) []byte, options ...Option) (int64, error) {
+	workingSlice, err := pipeline.apply(input, options)
+	if err != nil {
+		return 0, err
+	}
+
+	counter := &countingWriter{w: w}
+	buffered := bufio.NewWriter(counter)
+
+	for _, val := range workingSlice {
+		if _, err := buffered.Write(format(val)); err != nil {
+			return counter.n, err
+		}
+	}
+
+	err = buffered.Flush()
+	return counter.n, err
+}
+
+// tallies bytes that made it to the underlying writer
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// Scan every row of a query result with scan, ready to feed a pipeline. rows is always closed, and
+// rows.Err() is checked once iteration ends.
+func FromRows[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) ([]T, error) {
+	return fromRows(rows, scan)
+}
+
+// the subset of *sql.Rows FromRows() needs
+type rowIterator interface {
+	Next() bool
+	Err() error
+	Close() error
+}
+
+func fromRows[T any, R rowIterator](rows R, scan func(R) (T, error)) (out []T, err error) {
+	defer func() {
+		if closeErr := rows.Close(); err == nil && closeErr != nil {
+			out, err = nil, fmt.Errorf("FromRows(): %w", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		row, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("FromRows(): row %v: %w", len(out), err)
+		}
+		out = append(out, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("FromRows(): %w", err)
+	}
+
+	return out, nil
+}
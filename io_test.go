@@ -0,0 +1,243 @@
+package derp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+type ioRecord struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	lines := [][]byte{
+		[]byte(`{"id": 1, "name": "a"}`),
+		[]byte(`{"id": 2, "name": "b"}`),
+		[]byte(`{"id": 3, "name": "c"}`),
+	}
+
+	expected := []ioRecord{{1, "a"}, {2, "b"}, {3, "c"}}
+	gotten, err := DecodeJSON[ioRecord](lines)
+	if err != nil {
+		t.Errorf("TestDecodeJSON(); error from DecodeJSON(): %v", err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestDecodeJSON(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+}
+
+func TestDecodeJSONErrors(t *testing.T) {
+	lines := [][]byte{
+		[]byte(`{"id": 1}`),
+		[]byte(`{"id": `),
+		[]byte(`not json`),
+	}
+
+	_, err := DecodeJSON[ioRecord](lines)
+	if err == nil || !strings.Contains(err.Error(), "element 1") {
+		t.Errorf("TestDecodeJSONErrors(); expected first error at element 1. Got: %v", err)
+	}
+
+	_, err = DecodeJSON[ioRecord](lines, Opt_CollectErrors)
+	if err == nil || !strings.Contains(err.Error(), "element 1") || !strings.Contains(err.Error(), "element 2") {
+		t.Errorf("TestDecodeJSONErrors(); expected errors for elements 1 and 2. Got: %v", err)
+	}
+
+	if _, err := DecodeJSON[ioRecord](nil); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("TestDecodeJSONErrors(); expected ErrEmptyInput. Got: %v", err)
+	}
+}
+
+func TestEncodeJSONTo(t *testing.T) {
+	records := []ioRecord{{1, "a"}, {2, "b"}, {3, "c"}}
+
+	var out bytes.Buffer
+	if err := EncodeJSONTo(records, &out); err != nil {
+		t.Errorf("TestEncodeJSONTo(); error from EncodeJSONTo(): %v", err)
+	}
+
+	expected := "{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n{\"id\":3,\"name\":\"c\"}\n"
+	if out.String() != expected {
+		t.Errorf("TestEncodeJSONTo(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, out.String())
+	}
+
+	if err := EncodeJSONTo([]func(){func() {}}, &out); err == nil {
+		t.Errorf("TestEncodeJSONTo(); expected marshal error for func values")
+	}
+}
+
+func TestFromLines(t *testing.T) {
+	long := strings.Repeat("x", 100_000) // beyond bufio.Scanner's default limit
+	input := "first\r\nsecond\n" + long + "\nlast"
+
+	expected := []string{"first", "second", long, "last"}
+	gotten, err := FromLines(strings.NewReader(input))
+	if err != nil {
+		t.Errorf("TestFromLines(); error from FromLines(): %v", err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestFromLines(); value mismatch. Got %v lines", len(gotten))
+	}
+
+	var streamed []string
+	for line, err := range Lines(strings.NewReader(input)) {
+		if err != nil {
+			t.Errorf("TestFromLines(); error from Lines(): %v", err)
+		}
+		streamed = append(streamed, line)
+	}
+
+	if !slices.Equal(expected, streamed) {
+		t.Errorf("TestFromLines(); streamed value mismatch. Got %v lines", len(streamed))
+	}
+}
+
+func TestFromLinesError(t *testing.T) {
+	readErr := errors.New("disk on fire")
+
+	if _, err := FromLines(io.MultiReader(strings.NewReader("a\n"), iotest.ErrReader(readErr))); !errors.Is(err, readErr) {
+		t.Errorf("TestFromLinesError(); expected read error. Got: %v", err)
+	}
+}
+
+func TestFromCSV(t *testing.T) {
+	input := "1,a\n2,\"b, quoted\"\n"
+
+	records, err := FromCSV(strings.NewReader(input))
+	if err != nil {
+		t.Errorf("TestFromCSV(); error from FromCSV(): %v", err)
+	}
+
+	if len(records) != 2 || records[1][1] != "b, quoted" {
+		t.Errorf("TestFromCSV(); value mismatch. Got: [%v]\n", records)
+	}
+
+	if _, err := FromCSV(strings.NewReader("1,a\n2\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("TestFromCSV(); expected parse error on line 2. Got: %v", err)
+	}
+}
+
+func TestFromCSVFunc(t *testing.T) {
+	input := "# id;name\n1; a\n2; b\n"
+	opts := CSVOptions{Comma: ';', Comment: '#', TrimLeadingSpace: true}
+
+	toRecord := func(record []string) (ioRecord, error) {
+		id, err := strconv.Atoi(record[0])
+		return ioRecord{ID: id, Name: record[1]}, err
+	}
+
+	expected := []ioRecord{{1, "a"}, {2, "b"}}
+	gotten, err := FromCSVFunc(strings.NewReader(input), opts, toRecord)
+	if err != nil {
+		t.Errorf("TestFromCSVFunc(); error from FromCSVFunc(): %v", err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestFromCSVFunc(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	_, err = FromCSVFunc(strings.NewReader("1;a\nx;b\n"), opts, toRecord)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("TestFromCSVFunc(); expected conversion error on line 2. Got: %v", err)
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	var pipe Pipeline[int]
+
+	pipe.Filter(func(value int) bool {
+		return value%2 == 0
+	})
+
+	format := func(value int) []byte {
+		return []byte(strconv.Itoa(value) + "\n")
+	}
+
+	var out bytes.Buffer
+	n, err := pipe.WriteTo(numbers, &out, format)
+	if err != nil {
+		t.Errorf("TestWriteTo(); error from WriteTo(): %v", err)
+	}
+
+	expected := "2\n4\n6\n8\n10\n"
+	if out.String() != expected || n != int64(len(expected)) {
+		t.Errorf("TestWriteTo(); value mismatch.\nExpected: [%q] (%v bytes) Got: [%q] (%v bytes)\n", expected, len(expected), out.String(), n)
+	}
+
+	writeErr := errors.New("socket closed")
+	if _, err := pipe.WriteTo(numbers, errWriter{writeErr}, format); !errors.Is(err, writeErr) {
+		t.Errorf("TestWriteTo(); expected write error. Got: %v", err)
+	}
+}
+
+type errWriter struct {
+	err error
+}
+
+func (ew errWriter) Write(p []byte) (int, error) {
+	return 0, ew.err
+}
+
+type fakeRows struct {
+	values []int
+	cursor int
+	err    error
+	closed bool
+}
+
+func (rows *fakeRows) Next() bool {
+	if rows.cursor >= len(rows.values) {
+		return false
+	}
+	rows.cursor++
+	return true
+}
+
+func (rows *fakeRows) Err() error   { return rows.err }
+func (rows *fakeRows) Close() error { rows.closed = true; return nil }
+
+func TestFromRows(t *testing.T) {
+	scan := func(rows *fakeRows) (int, error) {
+		return rows.values[rows.cursor-1], nil
+	}
+
+	rows := &fakeRows{values: []int{1, 2, 3}}
+
+	expected := []int{1, 2, 3}
+	gotten, err := fromRows(rows, scan)
+	if err != nil {
+		t.Errorf("TestFromRows(); error from fromRows(): %v", err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestFromRows(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if !rows.closed {
+		t.Errorf("TestFromRows(); rows not closed")
+	}
+
+	iterErr := errors.New("connection reset")
+	rows = &fakeRows{values: []int{1}, err: iterErr}
+
+	if _, err := fromRows(rows, scan); !errors.Is(err, iterErr) || !rows.closed {
+		t.Errorf("TestFromRows(); expected rows.Err() surfaced and rows closed. Got: %v", err)
+	}
+
+	scanErr := errors.New("bad column")
+	rows = &fakeRows{values: []int{1}}
+
+	if _, err := fromRows(rows, func(*fakeRows) (int, error) { return 0, scanErr }); !errors.Is(err, scanErr) || !rows.closed {
+		t.Errorf("TestFromRows(); expected scan error surfaced and rows closed. Got: %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package derp
+
+// Numeric aggregations.
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+)
+
+// Any built-in integer or floating point type.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Count values into bins fixed-width bins spanning [lo, hi]. Values below lo land in the first bin and
+// values above hi in the last; NaNs are skipped. Each worker fills a partial histogram that is summed at
+// the end. Requires bins >= 1 and hi > lo.
+func Histogram[T Numeric](in []T, lo, hi T, bins int) ([]int, error) {
+	return histogram(in, lo, hi, bins, false)
+}
+
+// Like Histogram, but values outside [lo, hi] are dropped instead of clamped into the edge bins.
+func HistogramDropOutliers[T Numeric](in []T, lo, hi T, bins int) ([]int, error) {
+	return histogram(in, lo, hi, bins, true)
+}
+
+func histogram[T Numeric](in []T, lo, hi T, bins int, dropOutliers bool) ([]int, error) {
+	if bins < 1 {
+		return nil, fmt.Errorf("Histogram(): %v bins: %w", bins, ErrInvalidCount)
+	}
+	if hi <= lo {
+		return nil, fmt.Errorf("Histogram(): max %v must be greater than min %v", hi, lo)
+	}
+
+	low, high := float64(lo), float64(hi)
+	width := (high - low) / float64(bins)
+
+	numWorkers := runtime.GOMAXPROCS(0)
+	partials := make([][]int, numWorkers)
+
+	parallelChunks(len(in), numWorkers, func(worker, start, end int) {
+		counts := make([]int, bins)
+		for _, v := range in[start:end] {
+			f := float64(v)
+			if math.IsNaN(f) {
+				continue
+			}
+			if dropOutliers && (v < lo || v > hi) {
+				continue
+			}
+
+			// clamp before converting, or ±Inf and huge values overflow int and land in bin 0
+			switch {
+			case f >= high:
+				counts[bins-1]++
+			case f < low:
+				counts[0]++
+			default:
+				counts[min(int(math.Floor((f-low)/width)), bins-1)]++
+			}
+		}
+		partials[worker] = counts
+	})
+
+	out := make([]int, bins)
+	for _, counts := range partials {
+		for idx, count := range counts {
+			out[idx] += count
+		}
+	}
+
+	return out, nil
+}
+
+// Sum in and count its items in one pass, eg. for a mean: sum / count. Chunks are summed concurrently,
+// so float sums can differ from a serial sum in the last bits. An empty slice gives 0, 0.
+// Every value counts, so a single NaN makes the sum NaN, and +Inf plus -Inf is NaN too;
+// see SumAndCountFinite().
+func SumAndCount[T Numeric](in []T) (T, int) {
+	return sumAndCount(in, false)
+}
+
+// Like SumAndCount, but NaN and ±Inf are left out of both the sum and the count, eg. for sensor data that
+// marks missing readings with NaN. Integers are always finite, so for them it matches SumAndCount.
+func SumAndCountFinite[T Numeric](in []T) (T, int) {
+	return sumAndCount(in, true)
+}
+
+func sumAndCount[T Numeric](in []T, finiteOnly bool) (T, int) {
+	numWorkers := runtime.GOMAXPROCS(0)
+	partials := make([]T, numWorkers)
+	counts := make([]int, numWorkers)
+
+	parallelChunks(len(in), numWorkers, func(worker, start, end int) {
+		var sum T
+		count := 0
+		for _, v := range in[start:end] {
+			if finiteOnly {
+				if f := float64(v); math.IsNaN(f) || math.IsInf(f, 0) {
+					continue
+				}
+			}
+			sum += v
+			count++
+		}
+		partials[worker], counts[worker] = sum, count
+	})
+
+	var sum T
+	count := 0
+	for worker, partial := range partials {
+		sum += partial
+		count += counts[worker]
+	}
+
+	return sum, count
+}
+
+// Return the value at percentile p of in, for p in [0, 100].
+//
+// Uses the nearest-rank method: the result is the ceil(p/100 * len)-th smallest value (the smallest for p == 0),
+// so it is always an element of in and never interpolated. For 1..100, p50 is 50. Selection is an O(n)
+// quickselect over a copy; in is left untouched. NaNs sort below every other value.
+func Percentile[T Numeric](in []T, p float64) (T, error) {
+	var zero T
+
+	if len(in) == 0 {
+		return zero, ErrEmptyInput
+	}
+	if !(p >= 0 && p <= 100) {
+		return zero, fmt.Errorf("Percentile(%v): %w [0, 100]", p, ErrOutOfRange)
+	}
+
+	rank := max(int(math.Ceil(p/100*float64(len(in)))), 1)
+
+	return quickselect(slices.Clone(in), rank-1), nil
+}
+
+// Partially order s in place so s[k] holds the k-th smallest value, and return it.
+func quickselect[T cmp.Ordered](s []T, k int) T {
+	lo, hi := 0, len(s)-1
+
+	for lo < hi {
+		pivot := s[lo+(hi-lo)/2]
+		i, j := lo, hi
+
+		for i <= j {
+			for cmp.Less(s[i], pivot) {
+				i++
+			}
+			for cmp.Less(pivot, s[j]) {
+				j--
+			}
+			if i <= j {
+				s[i], s[j] = s[j], s[i]
+				i++
+				j--
+			}
+		}
+
+		switch {
+		case k <= j:
+			hi = j
+		case k >= i:
+			lo = i
+		default:
+			return s[k]
+		}
+	}
+
+	return s[k]
+}
+
+// Register a Reduce that sums the items. Because addition is associative, Apply() sums chunks concurrently
+// and then adds the partial sums, instead of folding serially like a general Reduce. Float results can
+// differ from a serial sum in the last bits. Shares Reduce's single slot.
+func ReduceSum[T Numeric](pipeline *Pipeline[T], comments ...string) error {
+	err := pipeline.Reduce(func(acc, value T) T {
+		return acc + value
+	}, comments...)
+	if err != nil {
+		return err
+	}
+
+	pipeline.reduceAssociative = true
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package derp
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	numbers := []int{-5, 0, 1, 4, 5, 9, 10, 15}
+
+	expected := []int{4, 4}
+	gotten, err := Histogram(numbers, 0, 10, 2)
+	if err != nil {
+		t.Errorf("TestHistogram(); error from Histogram(): %v", err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestHistogram(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	expected = []int{3, 3}
+	gotten, err = HistogramDropOutliers(numbers, 0, 10, 2)
+	if err != nil {
+		t.Errorf("TestHistogram(); error from HistogramDropOutliers(): %v", err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestHistogram(); dropped outliers value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	extremes := []float64{math.Inf(1), 1e300, math.MaxFloat64, 5, math.Inf(-1), -math.MaxFloat64}
+
+	expected = []int{2, 4}
+	gotten, err = Histogram(extremes, 0, 10, 2)
+	if err != nil {
+		t.Errorf("TestHistogram(); error from Histogram(): %v", err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestHistogram(); extremes value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	if _, err := Histogram(numbers, 0, 10, 0); err == nil {
+		t.Errorf("TestHistogram(); expected error for bins < 1")
+	}
+
+	if _, err := Histogram(numbers, 10, 10, 2); err == nil {
+		t.Errorf("TestHistogram(); expected error for max <= min")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	numbers := make([]int, 100)
+	for idx := range numbers {
+		numbers[len(numbers)-1-idx] = idx + 1 // descending, so selection has work to do
+	}
+
+	for p, expected := range map[float64]int{0: 1, 50: 50, 95: 95, 99: 99, 100: 100} {
+		gotten, err := Percentile(numbers, p)
+		if err != nil {
+			t.Errorf("TestPercentile(); error from Percentile(): %v", err)
+		}
+
+		if gotten != expected {
+			t.Errorf("TestPercentile(); p%v mismatch.\nExpected: [%v] Got: [%v]\n", p, expected, gotten)
+		}
+	}
+
+	if numbers[0] != 100 {
+		t.Errorf("TestPercentile(); input reordered")
+	}
+
+	if _, err := Percentile(numbers, 101); err == nil {
+		t.Errorf("TestPercentile(); expected error for p > 100")
+	}
+
+	if _, err := Percentile([]int{}, 50); err == nil {
+		t.Errorf("TestPercentile(); expected error for empty input")
+	}
+}
+
+func TestSumAndCount(t *testing.T) {
+	input := Generate(10, func(index int) float64 { return float64(index + 1) })
+
+	sum, count := SumAndCount(input)
+	if mean := sum / float64(count); mean != 5.5 || count != 10 {
+		t.Errorf("TestSumAndCount(); value mismatch.\nExpected: [5.5 over 10] Got: [%v over %v]\n", mean, count)
+	}
+
+	if sum, count := SumAndCount([]int{}); sum != 0 || count != 0 {
+		t.Errorf("TestSumAndCount(); expected 0, 0 for empty input. Got: [%v, %v]\n", sum, count)
+	}
+}
+
+func TestSumAndCountFinite(t *testing.T) {
+	input := []float64{1, math.NaN(), 2}
+
+	if sum, count := SumAndCountFinite(input); sum != 3 || count != 2 {
+		t.Errorf("TestSumAndCountFinite(); value mismatch.\nExpected: [3 over 2] Got: [%v over %v]\n", sum, count)
+	}
+
+	if sum, _ := SumAndCount(input); !math.IsNaN(sum) {
+		t.Errorf("TestSumAndCountFinite(); expected SumAndCount() to keep NaN. Got: [%v]\n", sum)
+	}
+
+	if sum, count := SumAndCountFinite([]float32{float32(math.Inf(1)), 4, float32(math.Inf(-1))}); sum != 4 || count != 1 {
+		t.Errorf("TestSumAndCountFinite(); Inf mismatch.\nExpected: [4 over 1] Got: [%v over %v]\n", sum, count)
+	}
+}
+
+func TestReduceSum(t *testing.T) {
+	for _, size := range []int{1, 3, 5, 10_000} {
+		input := Range(size)
+
+		var pipeline Pipeline[int]
+		pipeline.SetSerialThreshold(0)
+		if err := ReduceSum(&pipeline); err != nil {
+			t.Fatal(err)
+		}
+
+		gotten, err := pipeline.Apply(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := size * (size - 1) / 2
+		if !slices.Equal(gotten, []int{expected}) {
+			t.Errorf("TestReduceSum(); value mismatch for %v items.\nExpected: [[%v]] Got: [%v]\n", size, expected, gotten)
+		}
+
+		if err := ReduceSum(&pipeline); !errors.Is(err, ErrReduceAlreadySet) {
+			t.Errorf("TestReduceSum(); expected ErrReduceAlreadySet. Got: [%v]\n", err)
+		}
+	}
+}
+
+func benchmarkSum(b *testing.B, register func(*Pipeline[int])) {
+	var pipeline Pipeline[int]
+	pipeline.Filter(func(value int) bool { return value%3 != 0 })
+	register(&pipeline)
+
+	input := benchmarkInput(1_000_000)
+
+	for b.Loop() {
+		pipeline.Apply(input)
+	}
+}
+
+func BenchmarkReduceSerialSum(b *testing.B) {
+	benchmarkSum(b, func(p *Pipeline[int]) { p.Reduce(func(acc, value int) int { return acc + value }) })
+}
+
+func BenchmarkReduceSum(b *testing.B) {
+	benchmarkSum(b, func(p *Pipeline[int]) { ReduceSum(p) })
+}
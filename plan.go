@@ -0,0 +1,83 @@
+package derp
+
+// Structural comparison of pipelines, eg. to lock down a plan in tests without running it.
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Report whether pipeline and other hold the same orders in the same sequence: same adapters, indexes,
+// comments, stage workers and counts or seeds. Functions can't be compared and are ignored.
+// Note Apply() moves Reduce to the end, so compare pipelines at the same point in their life.
+func (pipeline *Pipeline[T]) PlanEqual(other *Pipeline[T]) bool {
+	return pipeline.PlanDiff(other) == ""
+}
+
+// Describe how other's orders differ from pipeline's, one line per differing position, or "" if PlanEqual.
+func (pipeline *Pipeline[T]) PlanDiff(other *Pipeline[T]) string {
+	var out strings.Builder
+
+	for idx := range max(len(pipeline.orders), len(other.orders)) {
+		var mine, theirs string
+		if idx < len(pipeline.orders) {
+			mine = pipeline.describeOrder(pipeline.orders[idx])
+		}
+		if idx < len(other.orders) {
+			theirs = other.describeOrder(other.orders[idx])
+		}
+
+		switch {
+		case mine == theirs:
+		case theirs == "":
+			fmt.Fprintf(&out, "Order %v: %v: missing from other\n", idx+1, mine)
+		case mine == "":
+			fmt.Fprintf(&out, "Order %v: %v: only in other\n", idx+1, theirs)
+		default:
+			fmt.Fprintf(&out, "Order %v: %v != %v\n", idx+1, mine, theirs)
+		}
+	}
+
+	return out.String()
+}
+
+// One order as text: adapter, index, and whatever non-function settings it carries.
+func (pipeline *Pipeline[T]) describeOrder(ord order) string {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "%v #%v", ord.method, ord.index)
+
+	switch ord.method {
+	case "foreachAsync":
+		fmt.Fprintf(&out, " concurrency=%v", pipeline.foreachAsyncs[ord.index].concurrency)
+	case "reduce":
+		switch {
+		case pipeline.reduceIndexed != nil:
+			out.WriteString(" indexed")
+		case pipeline.reduceWhile != nil:
+			out.WriteString(" while")
+		case pipeline.reduceRight:
+			out.WriteString(" right")
+		case pipeline.reduceAssociative:
+			out.WriteString(" associative")
+		}
+	case "sample":
+		fmt.Fprintf(&out, " k=%v seed=%v", pipeline.samples[ord.index].k, pipeline.samples[ord.index].seed)
+	case "shuffle":
+		fmt.Fprintf(&out, " seed=%v", pipeline.shuffleSeeds[ord.index])
+	case "skip":
+		fmt.Fprintf(&out, " n=%v", pipeline.skipCounts[ord.index])
+	case "take":
+		fmt.Fprintf(&out, " n=%v", pipeline.takeCounts[ord.index])
+	}
+
+	if ord.workers > 0 {
+		fmt.Fprintf(&out, " workers=%v", ord.workers)
+	}
+
+	if len(ord.comments) > 0 {
+		fmt.Fprintf(&out, " %q", ord.comments)
+	}
+
+	return out.String()
+}
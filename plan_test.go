@@ -0,0 +1,32 @@
+package derp
+
+import (
+	"testing"
+)
+
+func TestPlanDiff(t *testing.T) {
+	build := func(skip int) *Pipeline[int] {
+		var pipeline Pipeline[int]
+		pipeline.Filter(func(value int) bool { return value > 0 }, "positives")
+		pipeline.Map(func(index int, value int) int { return value * 2 })
+		pipeline.Skip(skip)
+		return &pipeline
+	}
+
+	a, b := build(1), build(1)
+	if !a.PlanEqual(b) || a.PlanDiff(b) != "" {
+		t.Errorf("TestPlanDiff(); identical plans reported different.\nGot: [%v]\n", a.PlanDiff(b))
+	}
+
+	c := build(2)
+	expected := `Order 3: skip #0 n=1 ["skip(1)"] != skip #0 n=2 ["skip(2)"]` + "\n"
+	if a.PlanEqual(c) || a.PlanDiff(c) != expected {
+		t.Errorf("TestPlanDiff(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, a.PlanDiff(c))
+	}
+
+	c.Take(5)
+	expected += `Order 4: take #0 n=5 ["take(5)"]: only in other` + "\n"
+	if gotten := a.PlanDiff(c); gotten != expected {
+		t.Errorf("TestPlanDiff(); extra stage mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+}
@@ -0,0 +1,93 @@
+package derp
+
+// Long-lived workers shared across Apply calls.
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A fixed set of goroutines that ApplyPool() hands stage chunks to, so servers running many small applies
+// don't start fresh goroutines for every stage of every call. Safe to share between pipelines and goroutines.
+type WorkerPool struct {
+	tasks     chan func()
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Start size workers. Call Close() once the pool is no longer needed.
+func NewWorkerPool(size int) (*WorkerPool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("NewWorkerPool(%v): %w", size, ErrInvalidCount)
+	}
+
+	pool := &WorkerPool{
+		tasks:  make(chan func()),
+		closed: make(chan struct{}),
+	}
+
+	for range size {
+		go func() {
+			for task := range pool.tasks {
+				task()
+			}
+		}()
+	}
+
+	return pool, nil
+}
+
+// Stop the workers once they finish their current chunk. Must not race with a running ApplyPool().
+// Later ApplyPool() calls return ErrPoolClosed.
+func (pool *WorkerPool) Close() {
+	pool.closeOnce.Do(func() {
+		close(pool.closed)
+		close(pool.tasks)
+	})
+}
+
+func (pool *WorkerPool) isClosed() bool {
+	select {
+	case <-pool.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Like parallelChunks(), but chunks go to an idle pool worker. With none idle, eg. when several applies
+// share the pool or a user function calls ApplyPool() itself, the chunk gets its own goroutine instead
+// of waiting, so a busy pool never deadlocks.
+func (pool *WorkerPool) parallelChunks(length, numWorkers int, fn func(worker, start, end int)) {
+	spreadChunks(length, numWorkers, fn, func(task func()) {
+		select {
+		case pool.tasks <- task:
+		default:
+			go task()
+		}
+	})
+}
+
+// Like Apply(), but every parallel stage runs its chunks on pool's workers. The number of chunks per stage
+// still comes from the power options and WithStageWorkers(); the serial threshold still applies.
+// ForeachAsync keeps its own goroutines.
+func (pipeline *Pipeline[T]) ApplyPool(input []T, pool *WorkerPool, options ...Option) ([]T, error) {
+	if pool == nil || pool.isClosed() {
+		return nil, fmt.Errorf("ApplyPool(): %w", ErrPoolClosed)
+	}
+
+	pipeline.pool = pool
+	defer func() { pipeline.pool = nil }()
+
+	return pipeline.Apply(input, options...)
+}
+
+// Split a stage across workers, on the pool during ApplyPool() and on fresh goroutines otherwise.
+func (pipeline *Pipeline[T]) parallelChunks(length, numWorkers int, fn func(worker, start, end int)) {
+	if pipeline.pool != nil {
+		pipeline.pool.parallelChunks(length, numWorkers, fn)
+		return
+	}
+
+	parallelChunks(length, numWorkers, fn)
+}
@@ -0,0 +1,100 @@
+package derp
+
+import (
+	"errors"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestApplyPool(t *testing.T) {
+	pool, err := NewWorkerPool(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	build := func() *Pipeline[int] {
+		var pipeline Pipeline[int]
+		pipeline.SetSerialThreshold(0)
+		pipeline.Map(func(index int, value int) int { return value*2 + index })
+		pipeline.Filter(func(value int) bool { return value%3 != 0 })
+		return &pipeline
+	}
+
+	pipeline := build()
+	input := benchmarkInput(10_000)
+	expected, err := pipeline.Apply(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// several pipelines share the pool at once
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gotten, err := build().ApplyPool(input, pool)
+			if err != nil || !slices.Equal(gotten, expected) {
+				t.Errorf("TestApplyPool(); value mismatch. Got %v items, err: [%v]\n", len(gotten), err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// a stage that applies on the same pool from inside a worker must not deadlock
+	var outer Pipeline[int]
+	outer.SetSerialThreshold(0)
+	outer.Map(func(index int, value int) int {
+		var inner Pipeline[int]
+		inner.SetSerialThreshold(0)
+		inner.Map(func(index int, value int) int { return value + 1 })
+
+		out, err := inner.ApplyPool([]int{value, value}, pool)
+		if err != nil {
+			return -1
+		}
+		return out[1]
+	})
+
+	gotten, err := outer.ApplyPool(Range(runtime.GOMAXPROCS(0)*4), pool)
+	if err != nil || gotten[3] != 4 {
+		t.Errorf("TestApplyPool(); nested value mismatch.\nExpected: [4] Got: [%v %v]\n", gotten, err)
+	}
+
+	pool.Close()
+	if _, err := pipeline.ApplyPool(input, pool); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("TestApplyPool(); expected ErrPoolClosed. Got: [%v]\n", err)
+	}
+	if _, err := NewWorkerPool(0); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("TestApplyPool(); expected ErrInvalidCount. Got: [%v]\n", err)
+	}
+}
+
+func benchmarkSmallApplies(b *testing.B, pooled bool) {
+	var pipeline Pipeline[int]
+	pipeline.SetSerialThreshold(0)
+	pipeline.Map(func(index int, value int) int { return value * 2 })
+	pipeline.Filter(func(value int) bool { return value%3 != 0 })
+
+	pool, _ := NewWorkerPool(runtime.GOMAXPROCS(0))
+	defer pool.Close()
+
+	input := benchmarkInput(256)
+	b.ReportAllocs()
+
+	for b.Loop() {
+		for range 10_000 {
+			if pooled {
+				pipeline.ApplyPool(input, pool)
+			} else {
+				pipeline.Apply(input)
+			}
+		}
+	}
+}
+
+func BenchmarkSmallAppliesGoroutines(b *testing.B) { benchmarkSmallApplies(b, false) }
+func BenchmarkSmallAppliesPool(b *testing.B)       { benchmarkSmallApplies(b, true) }
@@ -0,0 +1,132 @@
+package derp
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Streaming quantile estimate using the P² algorithm (Jain & Chlamtac, 1985).
+//
+// Memory is constant (five markers) no matter how many values are added, which makes it suitable for
+// millions of values where the exact Percentile() sort is too expensive. The estimate is exact for the
+// first five values; after that, for smooth distributions, it typically lands within a percent or two of
+// the true quantile, degrading for heavy-tailed or multi-modal data and for extreme p.
+// Create with NewQuantile(). Not safe for concurrent use.
+type Quantile struct {
+	p       float64
+	count   int
+	heights [5]float64 // marker heights
+	pos     [5]float64 // actual marker positions, 1-based
+	desired [5]float64 // desired marker positions
+	step    [5]float64 // desired position increments per value
+}
+
+// Estimate percentile p, for p in (0, 100); eg. 50 for the median.
+func NewQuantile(p float64) (*Quantile, error) {
+	if !(p > 0 && p < 100) {
+		return nil, fmt.Errorf("NewQuantile(%v): %w (0, 100)", p, ErrOutOfRange)
+	}
+
+	p /= 100
+
+	return &Quantile{
+		p:       p,
+		pos:     [5]float64{1, 2, 3, 4, 5},
+		desired: [5]float64{1, 1 + 2*p, 1 + 4*p, 3 + 2*p, 5},
+		step:    [5]float64{0, p / 2, p, (1 + p) / 2, 1},
+	}, nil
+}
+
+// Feed one observation. NaNs are ignored.
+func (q *Quantile) Add(v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+
+	if q.count < 5 {
+		q.heights[q.count] = v
+		q.count++
+		if q.count == 5 {
+			slices.Sort(q.heights[:])
+		}
+		return
+	}
+	q.count++
+
+	// find the cell v falls in, stretching the extremes if needed
+	var k int
+	switch {
+	case v < q.heights[0]:
+		q.heights[0] = v
+		k = 0
+	case v >= q.heights[4]:
+		q.heights[4] = v
+		k = 3
+	default:
+		for k = 0; v >= q.heights[k+1]; k++ {
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		q.pos[i]++
+	}
+	for i := range q.desired {
+		q.desired[i] += q.step[i]
+	}
+
+	// nudge the middle markers toward their desired positions
+	for i := 1; i < 4; i++ {
+		d := q.desired[i] - q.pos[i]
+
+		if (d >= 1 && q.pos[i+1]-q.pos[i] > 1) || (d <= -1 && q.pos[i-1]-q.pos[i] < -1) {
+			d = math.Copysign(1, d)
+
+			if h := q.parabolic(i, d); q.heights[i-1] < h && h < q.heights[i+1] {
+				q.heights[i] = h
+			} else {
+				q.heights[i] = q.linear(i, d)
+			}
+			q.pos[i] += d
+		}
+	}
+}
+
+func (q *Quantile) parabolic(i int, d float64) float64 {
+	return q.heights[i] + d/(q.pos[i+1]-q.pos[i-1])*
+		((q.pos[i]-q.pos[i-1]+d)*(q.heights[i+1]-q.heights[i])/(q.pos[i+1]-q.pos[i])+
+			(q.pos[i+1]-q.pos[i]-d)*(q.heights[i]-q.heights[i-1])/(q.pos[i]-q.pos[i-1]))
+}
+
+func (q *Quantile) linear(i int, d float64) float64 {
+	j := i + int(d)
+	return q.heights[i] + d*(q.heights[j]-q.heights[i])/(q.pos[j]-q.pos[i])
+}
+
+// Current estimate; NaN before any value is added.
+func (q *Quantile) Value() float64 {
+	if q.count == 0 {
+		return math.NaN()
+	}
+
+	if q.count <= 5 { // exact, by nearest rank
+		seen := slices.Clone(q.heights[:q.count])
+		slices.Sort(seen)
+		return seen[max(int(math.Ceil(q.p*float64(q.count))), 1)-1]
+	}
+
+	return q.heights[2]
+}
+
+// Number of values added so far, NaNs excluded.
+func (q *Quantile) Count() int {
+	return q.count
+}
+
+// Adapt q for Pipeline.Foreach(), eg. pipeline.Foreach(Observe[int](q)). Don't combine with Opt_CFE;
+// Quantile is not safe for concurrent use.
+func Observe[T Numeric](q *Quantile) func(value T) {
+	return func(value T) {
+		q.Add(float64(value))
+	}
+}
@@ -0,0 +1,71 @@
+package derp
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestQuantile(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	numbers := make([]float64, 100_000)
+	for idx := range numbers {
+		numbers[idx] = rng.NormFloat64()*10 + 100
+	}
+
+	for _, p := range []float64{50, 95} {
+		q, err := NewQuantile(p)
+		if err != nil {
+			t.Fatalf("TestQuantile(); error from NewQuantile(): %v", err)
+		}
+
+		var pipe Pipeline[float64]
+		pipe.Foreach(Observe[float64](q))
+
+		if _, err := pipe.Apply(numbers); err != nil {
+			t.Errorf("TestQuantile(); error from Apply(): %v", err)
+		}
+
+		exact, _ := Percentile(numbers, p)
+
+		if estimate := q.Value(); math.Abs(estimate-exact)/exact > 0.01 {
+			t.Errorf("TestQuantile(); p%v estimate outside 1%% of exact.\nExpected: [%v] Got: [%v]\n", p, exact, estimate)
+		}
+
+		if q.Count() != len(numbers) {
+			t.Errorf("TestQuantile(); count mismatch.\nExpected: [%v] Got: [%v]\n", len(numbers), q.Count())
+		}
+	}
+}
+
+func TestQuantileSmall(t *testing.T) {
+	q, _ := NewQuantile(50)
+
+	if !math.IsNaN(q.Value()) {
+		t.Errorf("TestQuantileSmall(); expected NaN before any value")
+	}
+
+	for _, v := range []float64{3, 1, 2} {
+		q.Add(v)
+	}
+
+	if q.Value() != 2 {
+		t.Errorf("TestQuantileSmall(); expected exact median below five values.\nExpected: [2] Got: [%v]\n", q.Value())
+	}
+
+	for _, tc := range []struct{ p, expected float64 }{{99, 5}, {1, 1}, {50, 3}} {
+		q, _ := NewQuantile(tc.p)
+		for _, v := range []float64{4, 2, 5, 1, 3} {
+			q.Add(v)
+		}
+
+		if q.Value() != tc.expected {
+			t.Errorf("TestQuantileSmall(); p%v of exactly five values not exact.\nExpected: [%v] Got: [%v]\n", tc.p, tc.expected, q.Value())
+		}
+	}
+
+	if _, err := NewQuantile(100); err == nil {
+		t.Errorf("TestQuantileSmall(); expected error for p outside (0, 100)")
+	}
+}
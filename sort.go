@@ -0,0 +1,104 @@
+package derp
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// Below this many elements, the parallel sorts fall back to a serial sort.
+const parallelSortThreshold = 1 << 14
+
+// Reverse cmp, for descending keys in SortByKeys().
+func Desc[T any](cmp func(a, b T) int) func(a, b T) int {
+	return func(a, b T) int {
+		return cmp(b, a)
+	}
+}
+
+// Sort in in place with cmp, using every core on large slices.
+//
+// Each worker sorts a contiguous run, then runs are merged pairwise, with the merges at each level done
+// concurrently. Slices shorter than 16384 elements are sorted serially. Not stable; see ParallelSortStableFunc.
+func ParallelSortFunc[T any](in []T, cmp func(a, b T) int) {
+	parallelSort(in, cmp, slices.SortFunc[[]T], runtime.GOMAXPROCS(0))
+}
+
+// Like ParallelSortFunc, but equal elements keep their original order, as with slices.SortStableFunc.
+func ParallelSortStableFunc[T any](in []T, cmp func(a, b T) int) {
+	parallelSort(in, cmp, slices.SortStableFunc[[]T], runtime.GOMAXPROCS(0))
+}
+
+// parallelSort sorts numWorkers runs concurrently, then merges them pairwise, so no level
+// ever has more than numWorkers goroutines in flight.
+func parallelSort[T any](in []T, cmp func(a, b T) int, sortRun func([]T, func(a, b T) int), numWorkers int) {
+	if len(in) < parallelSortThreshold || numWorkers < 2 {
+		sortRun(in, cmp)
+		return
+	}
+
+	parallelChunks(len(in), numWorkers, func(_, start, end int) {
+		sortRun(in[start:end], cmp)
+	})
+
+	// run r spans [bounds[r], bounds[r+1]), matching parallelChunks' split
+	var bounds []int
+	chunkSize := (len(in) + numWorkers - 1) / numWorkers
+	for start := 0; start < len(in); start += chunkSize {
+		bounds = append(bounds, start)
+	}
+	bounds = append(bounds, len(in))
+
+	src, dst := in, make([]T, len(in))
+
+	for len(bounds) > 2 {
+		var next []int
+		var wg sync.WaitGroup
+
+		for r := 0; r+1 < len(bounds); r += 2 {
+			lo := bounds[r]
+			next = append(next, lo)
+
+			if r+2 >= len(bounds) { // odd run out, carry it over
+				copy(dst[lo:], src[lo:bounds[r+1]])
+				continue
+			}
+
+			mid, hi := bounds[r+1], bounds[r+2]
+
+			wg.Add(1)
+			go func(lo, mid, hi int) {
+				defer wg.Done()
+				mergeRuns(dst[lo:hi], src[lo:mid], src[mid:hi], cmp)
+			}(lo, mid, hi)
+		}
+
+		wg.Wait()
+
+		bounds = append(next, len(in))
+		src, dst = dst, src
+	}
+
+	if &src[0] != &in[0] {
+		copy(in, src)
+	}
+}
+
+// Merge sorted left and right into out. Ties take from left, keeping the merge stable.
+func mergeRuns[T any](out, left, right []T, cmp func(a, b T) int) {
+	i, j, k := 0, 0, 0
+
+	for i < len(left) && j < len(right) {
+		if cmp(right[j], left[i]) < 0 {
+			out[k] = right[j]
+			j++
+		} else {
+			out[k] = left[i]
+			i++
+		}
+		k++
+	}
+
+	k += copy(out[k:], left[i:])
+	copy(out[k:], right[j:])
+}
@@ -0,0 +1,144 @@
+package derp
+
+import (
+	"cmp"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelSortFunc(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(5)) // odd run count exercises the carry-over
+
+	for _, size := range []int{10, parallelSortThreshold * 3} {
+		numbers := benchmarkInput(size)
+
+		expected := slices.Clone(numbers)
+		slices.Sort(expected)
+
+		ParallelSortFunc(numbers, cmp.Compare[int])
+
+		if !slices.Equal(expected, numbers) {
+			t.Errorf("TestParallelSortFunc(); size %v not sorted", size)
+		}
+	}
+}
+
+func TestParallelSortStableFunc(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	pairs := make([]Pair[int, int], parallelSortThreshold*3)
+	for idx, val := range benchmarkInput(len(pairs)) {
+		pairs[idx] = Pair[int, int]{val % 100, idx} // many equal keys
+	}
+
+	byKey := func(a, b Pair[int, int]) int {
+		return cmp.Compare(a.First, b.First)
+	}
+
+	expected := slices.Clone(pairs)
+	slices.SortStableFunc(expected, byKey)
+
+	ParallelSortStableFunc(pairs, byKey)
+
+	if !slices.Equal(expected, pairs) {
+		t.Errorf("TestParallelSortStableFunc(); result differs from slices.SortStableFunc")
+	}
+}
+
+func TestSortStageWorkers(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+
+	var inFlight, peak atomic.Int32
+
+	var pipeline Pipeline[int]
+	pipeline.SortByKeys([]func(a, b int) int{func(a, b int) int {
+		now := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for old := peak.Load(); now > old && !peak.CompareAndSwap(old, now); old = peak.Load() {
+		}
+		if a%64 == 0 {
+			runtime.Gosched() // give the other runs a chance to overlap
+		}
+
+		return cmp.Compare(a, b)
+	}})
+
+	if err := pipeline.WithStageWorkers(0, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	input := benchmarkInput(parallelSortThreshold * 4)
+	expected := slices.Clone(input)
+	slices.Sort(expected)
+
+	gotten, err := pipeline.Apply(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestSortStageWorkers(); result not sorted")
+	}
+	if got := peak.Load(); got > 2 {
+		t.Errorf("TestSortStageWorkers(); concurrency mismatch.\nExpected: [<= 2] Got: [%v]\n", got)
+	}
+}
+
+func benchmarkSort(b *testing.B, size int, sort func([]int, func(a, b int) int)) {
+	numbers := benchmarkInput(size)
+	working := make([]int, size)
+
+	for b.Loop() {
+		copy(working, numbers)
+		sort(working, cmp.Compare[int])
+	}
+}
+
+func BenchmarkSerialSort10K(b *testing.B)   { benchmarkSort(b, 10_000, slices.SortFunc[[]int]) }
+func BenchmarkParallelSort10K(b *testing.B) { benchmarkSort(b, 10_000, ParallelSortFunc[int]) }
+func BenchmarkSerialSort1M(b *testing.B)    { benchmarkSort(b, 1_000_000, slices.SortFunc[[]int]) }
+func BenchmarkParallelSort1M(b *testing.B)  { benchmarkSort(b, 1_000_000, ParallelSortFunc[int]) }
+
+func TestSortByKeys(t *testing.T) {
+	type employee struct {
+		department string
+		name       string
+		age        int
+	}
+
+	staff := []employee{
+		{"ops", "bob", 30},
+		{"dev", "eve", 25},
+		{"ops", "amy", 41},
+		{"dev", "dan", 25},
+		{"dev", "eve", 52},
+	}
+
+	var pipe Pipeline[employee]
+
+	pipe.SortByKeys([]func(a, b employee) int{
+		func(a, b employee) int { return cmp.Compare(a.department, b.department) },
+		func(a, b employee) int { return cmp.Compare(a.name, b.name) },
+		Desc(func(a, b employee) int { return cmp.Compare(a.age, b.age) }),
+	}, "ORDER BY department, name, age DESC")
+
+	expected := []employee{
+		{"dev", "dan", 25},
+		{"dev", "eve", 52},
+		{"dev", "eve", 25},
+		{"ops", "amy", 41},
+		{"ops", "bob", 30},
+	}
+
+	gotten, err := pipe.Apply(staff)
+	if err != nil {
+		t.Errorf("TestSortByKeys(); error from Apply(): %v", err)
+	}
+
+	if !slices.Equal(expected, gotten) {
+		t.Errorf("TestSortByKeys(); value mismatch.\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+}
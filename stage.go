@@ -0,0 +1,102 @@
+package derp
+
+// Declarative pipeline construction, eg. from plugin or config definitions.
+
+import (
+	"fmt"
+	"slices"
+)
+
+// One order in a pipeline, described as data. Kind picks the method Build() replays and which of the
+// other fields it reads:
+//   - "filter" : Filter
+//   - "foreach" : Foreach
+//   - "foreachIndexed" : ForeachIndexed
+//   - "map" : Map
+//   - "reduce", "reduceRight", "reduceHere" : Reduce
+//   - "sample" : N and Seed
+//   - "shuffle" : Seed
+//   - "skip", "take" : N
+//   - "sort" : Sort, applied like SortByKeys()
+//
+// Comments are passed through where the method accepts them.
+type Stage[T any] struct {
+	Kind string
+
+	Filter         func(value T) bool
+	Foreach        func(value T)
+	ForeachIndexed func(index int, value T)
+	Map            func(index int, value T) T
+	Reduce         func(acc T, value T) T
+	Sort           []func(a, b T) int
+
+	N    int
+	Seed int64
+
+	Comments []string
+}
+
+// Construct a pipeline by replaying stages in order. Unlike the methods it calls, Build() never panics:
+// a missing function is reported as ErrNilFunc and an unrecognised Kind as ErrUnknownStage.
+func Build[T any](stages []Stage[T]) (*Pipeline[T], error) {
+	var pipeline Pipeline[T]
+
+	for idx, stage := range stages {
+		if err := pipeline.addStage(stage); err != nil {
+			return nil, fmt.Errorf("Build(): stage %v (%v): %w", idx, stage.Kind, err)
+		}
+	}
+
+	return &pipeline, nil
+}
+
+func (pipeline *Pipeline[T]) addStage(stage Stage[T]) error {
+	switch stage.Kind {
+	case "filter":
+		if stage.Filter == nil {
+			return ErrNilFunc
+		}
+		pipeline.Filter(stage.Filter, stage.Comments...)
+	case "foreach":
+		if stage.Foreach == nil {
+			return ErrNilFunc
+		}
+		pipeline.Foreach(stage.Foreach, stage.Comments...)
+	case "foreachIndexed":
+		if stage.ForeachIndexed == nil {
+			return ErrNilFunc
+		}
+		pipeline.ForeachIndexed(stage.ForeachIndexed, stage.Comments...)
+	case "map":
+		if stage.Map == nil {
+			return ErrNilFunc
+		}
+		pipeline.Map(stage.Map, stage.Comments...)
+	case "reduce":
+		return pipeline.Reduce(stage.Reduce, stage.Comments...)
+	case "reduceRight":
+		return pipeline.ReduceRight(stage.Reduce, stage.Comments...)
+	case "reduceHere":
+		if stage.Reduce == nil {
+			return ErrNilFunc
+		}
+		pipeline.ReduceHere(stage.Reduce, stage.Comments...)
+	case "sample":
+		return pipeline.Sample(stage.N, stage.Seed)
+	case "shuffle":
+		return pipeline.Shuffle(stage.Seed)
+	case "skip":
+		return pipeline.Skip(stage.N)
+	case "take":
+		return pipeline.Take(stage.N)
+	case "sort":
+		if len(stage.Sort) == 0 || slices.ContainsFunc(stage.Sort, func(cmp func(a, b T) int) bool { return cmp == nil }) {
+			return ErrNilFunc
+		}
+		pipeline.SortByKeys(stage.Sort, stage.Comments...)
+	default:
+		return ErrUnknownStage
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package derp
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	pipeline, err := Build([]Stage[int]{
+		{Kind: "filter", Filter: func(value int) bool { return value%2 == 0 }, Comments: []string{"evens"}},
+		{Kind: "map", Map: func(index int, value int) int { return value * 10 }},
+		{Kind: "take", N: 2},
+	})
+	if err != nil {
+		t.Fatalf("TestBuild(); error from Build(): %v", err)
+	}
+
+	gotten, err := pipeline.Apply([]int{1, 2, 3, 4, 5, 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(gotten, []int{20, 40}) {
+		t.Errorf("TestBuild(); value mismatch.\nExpected: [[20 40]] Got: [%v]\n", gotten)
+	}
+
+	if _, err := Build([]Stage[int]{{Kind: "explode"}}); !errors.Is(err, ErrUnknownStage) {
+		t.Errorf("TestBuild(); expected ErrUnknownStage. Got: [%v]\n", err)
+	}
+
+	if _, err := Build([]Stage[int]{{Kind: "map"}}); !errors.Is(err, ErrNilFunc) {
+		t.Errorf("TestBuild(); expected ErrNilFunc. Got: [%v]\n", err)
+	}
+
+	if _, err := Build([]Stage[int]{{Kind: "skip", N: -1}}); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("TestBuild(); expected ErrInvalidCount. Got: [%v]\n", err)
+	}
+}
@@ -0,0 +1,93 @@
+package derp
+
+// Latency bookkeeping across many Apply() calls.
+
+import (
+	"sync"
+	"time"
+)
+
+// Aggregate wall-clock time of the successful Apply() calls since WithStats(). Percentiles are P² estimates
+// (see Quantile), so approximate once more than five calls have been seen.
+type StatsSnapshot struct {
+	Name          string // from WithName()
+	Count         int
+	Total         time.Duration
+	Min, Max      time.Duration
+	P50, P95, P99 time.Duration
+}
+
+// Accumulator behind WithStats(); guarded by mu so it can be fed from any goroutine.
+type applyStats struct {
+	mu            sync.Mutex
+	count         int
+	total         time.Duration
+	min, max      time.Duration
+	p50, p95, p99 *Quantile
+}
+
+// Start recording how long each Apply() takes. Every Apply variant, the terminal operations (First,
+// Contains, ...), ApplyCached() hits and Tee() count as one call each, however many batches or branches they
+// run. Calling it again starts over.
+func (pipeline *Pipeline[T]) WithStats() {
+	p50, _ := NewQuantile(50)
+	p95, _ := NewQuantile(95)
+	p99, _ := NewQuantile(99)
+
+	pipeline.stats = &applyStats{p50: p50, p95: p95, p99: p99}
+}
+
+// Current aggregate latencies, labelled with the pipeline's name. Zero when WithStats() hasn't been called or nothing has run yet.
+func (pipeline *Pipeline[T]) Stats() StatsSnapshot {
+	if pipeline.stats == nil {
+		return StatsSnapshot{Name: pipeline.name}
+	}
+
+	snap := pipeline.stats.snapshot()
+	snap.Name = pipeline.name
+
+	return snap
+}
+
+// Feed one successful call that began at start to WithStats(), if it's on. Must run before Opt_Reset
+// clears the pipeline.
+func (pipeline *Pipeline[T]) recordSince(start time.Time) {
+	if pipeline.stats != nil && !pipeline.batching {
+		pipeline.stats.record(time.Since(start))
+	}
+}
+
+func (stats *applyStats) record(elapsed time.Duration) {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	if stats.count == 0 || elapsed < stats.min {
+		stats.min = elapsed
+	}
+	stats.max = max(stats.max, elapsed)
+	stats.count++
+	stats.total += elapsed
+
+	for _, q := range []*Quantile{stats.p50, stats.p95, stats.p99} {
+		q.Add(float64(elapsed))
+	}
+}
+
+func (stats *applyStats) snapshot() StatsSnapshot {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	if stats.count == 0 {
+		return StatsSnapshot{}
+	}
+
+	return StatsSnapshot{
+		Count: stats.count,
+		Total: stats.total,
+		Min:   stats.min,
+		Max:   stats.max,
+		P50:   time.Duration(stats.p50.Value()),
+		P95:   time.Duration(stats.p95.Value()),
+		P99:   time.Duration(stats.p99.Value()),
+	}
+}
@@ -0,0 +1,118 @@
+package derp
+
+import (
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	var pipeline Pipeline[int]
+
+	if snap := pipeline.Stats(); snap != (StatsSnapshot{}) {
+		t.Errorf("TestStats(); expected a zero snapshot before WithStats(). Got: [%+v]\n", snap)
+	}
+
+	pipeline.WithStats()
+	pipeline.Foreach(func(value int) { time.Sleep(time.Millisecond) })
+
+	for range 20 {
+		if _, err := pipeline.Apply([]int{1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pipeline.Apply(nil) // errors aren't recorded
+
+	snap := pipeline.Stats()
+	if snap.Count != 20 {
+		t.Errorf("TestStats(); count mismatch.\nExpected: [20] Got: [%v]\n", snap.Count)
+	}
+	if snap.Min < time.Millisecond || snap.Total < 20*time.Millisecond {
+		t.Errorf("TestStats(); durations too short. Got: [%+v]\n", snap)
+	}
+	if !(snap.Min <= snap.P50 && snap.P50 <= snap.P99 && snap.P99 <= snap.Max) {
+		t.Errorf("TestStats(); expected Min <= P50 <= P99 <= Max. Got: [%+v]\n", snap)
+	}
+}
+
+func TestStatsVariants(t *testing.T) {
+	var pipeline, other Pipeline[int]
+	pipeline.Filter(func(value int) bool { return value > 1 })
+	pipeline.Map(func(index int, value int) int { return value * 10 })
+	pipeline.WithStats()
+	other.WithStats()
+
+	pool, err := NewWorkerPool(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	input := []int{1, 2, 3, 4}
+	eq := func(a, b int) bool { return a == b }
+	keyer := func(in []int) string { return strconv.Itoa(len(in)) }
+	format := func(value int) []byte { return []byte(strconv.Itoa(value)) }
+
+	variants := []struct {
+		name string
+		run  func() error
+	}{
+		{"Apply", func() error { _, err := pipeline.Apply(input); return err }},
+		{"ApplyOne", func() error { _, err := pipeline.ApplyOne(2); return err }},
+		{"ApplyInto", func() error { _, err := pipeline.ApplyInto(nil, input); return err }},
+		{"ApplyMut", func() error { _, err := pipeline.ApplyMut([]int{1, 2}); return err }},
+		{"ApplyWithRejects", func() error { _, _, err := pipeline.ApplyWithRejects(input); return err }},
+		{"ApplyChunked", func() error { _, err := pipeline.ApplyChunked(input); return err }},
+		{"ApplyBatched", func() error { _, err := pipeline.ApplyBatched(input, 1); return err }},
+		{"ApplyStop", func() error { _, err := pipeline.ApplyStop(input, make(chan struct{})); return err }},
+		{"ApplyPool", func() error { _, err := pipeline.ApplyPool(input, pool); return err }},
+		{"ApplyCached miss", func() error { _, err := pipeline.ApplyCached(input, keyer); return err }},
+		{"ApplyCached hit", func() error { _, err := pipeline.ApplyCached(input, keyer); return err }},
+		{"ApplyTrace", func() error { _, _, err := pipeline.ApplyTrace(input); return err }},
+		{"ApplyFirst", func() error { _, err := pipeline.ApplyFirst(input, 1); return err }},
+		{"First", func() error { _, _, err := pipeline.First(input); return err }},
+		{"Last", func() error { _, _, err := pipeline.Last(input); return err }},
+		{"Contains", func() error { _, err := pipeline.Contains(input, 30, eq); return err }},
+		{"IndexOf", func() error { _, err := pipeline.IndexOf(input, 30, eq); return err }},
+		{"WriteTo", func() error { _, err := pipeline.WriteTo(input, io.Discard, format); return err }},
+		{"Tee", func() error { _, _, err := Tee(input, &pipeline, &other); return err }},
+	}
+
+	for idx, variant := range variants {
+		if err := variant.run(); err != nil {
+			t.Fatalf("TestStatsVariants(); error from %v(): %v", variant.name, err)
+		}
+
+		if count := pipeline.Stats().Count; count != idx+1 {
+			t.Errorf("TestStatsVariants(); %v() not recorded exactly once.\nExpected: [%v] Got: [%v]\n", variant.name, idx+1, count)
+		}
+	}
+
+	if count := other.Stats().Count; count != 1 {
+		t.Errorf("TestStatsVariants(); Tee() branch b not recorded.\nExpected: [1] Got: [%v]\n", count)
+	}
+}
+
+func TestStatsConcurrentRecord(t *testing.T) {
+	var pipeline Pipeline[int]
+	pipeline.WithStats()
+
+	var wg sync.WaitGroup
+	for worker := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range 1000 {
+				pipeline.stats.record(time.Duration(worker*1000 + idx))
+			}
+		}()
+	}
+	wg.Wait()
+
+	snap := pipeline.Stats()
+	if snap.Count != 8000 || snap.Min != 0 || snap.Max != 7999 {
+		t.Errorf("TestStatsConcurrentRecord(); value mismatch. Got: [%+v]\n", snap)
+	}
+}
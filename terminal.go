@@ -0,0 +1,200 @@
+package derp
+
+// Terminal operations that boil a pipeline's output down to a single answer.
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Run the pipeline and return the first element of the result, with false if the result is empty.
+// Stops at the first survivor under the same conditions as ApplyFirst().
+func (pipeline *Pipeline[T]) First(input []T, options ...Option) (T, bool, error) {
+	var zero T
+
+	workingSlice, err := pipeline.ApplyFirst(input, 1, options...)
+	if err != nil || len(workingSlice) == 0 {
+		return zero, false, err
+	}
+
+	return workingSlice[0], true, nil
+}
+
+// Run the pipeline and return the first n elements of its result, eg. the first 5 items that pass every
+// Filter, which Take(5) placed before the Filter would not give. Fewer than n if the result is shorter.
+//
+// When every order is a Filter, Map, Skip or Take, input is streamed through the orders one element at a time
+// and stops once n have survived, so later elements are never cloned or visited. Map sees the same indexes
+// it would in a full Apply(). Anything else runs a full Apply() and truncates.
+func (pipeline *Pipeline[T]) ApplyFirst(input []T, n int, options ...Option) ([]T, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("ApplyFirst(%v): %w", n, ErrInvalidCount)
+	}
+
+	if !pipeline.streamable() {
+		workingSlice, err := pipeline.apply(input, options)
+		if err != nil {
+			return nil, err
+		}
+		return workingSlice[:min(n, len(workingSlice))], nil
+	}
+
+	start := time.Now()
+	if err := pipeline.checkApply(input, options); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		pipeline.recordSince(start)
+		if slices.Contains(options, Opt_Reset) {
+			*pipeline = Pipeline[T]{}
+		}
+	}()
+
+	out := make([]T, 0, min(n, len(input)))
+	if n == 0 {
+		return out, nil
+	}
+
+	pipeline.stream(input, options, func(val T) bool {
+		out = append(out, val)
+		return len(out) < n
+	})
+
+	return out, nil
+}
+
+// Run the pipeline and return the last element of the result, with false if the result is empty.
+// Always needs the full result, so there's no short-circuit.
+func (pipeline *Pipeline[T]) Last(input []T, options ...Option) (T, bool, error) {
+	var zero T
+
+	workingSlice, err := pipeline.apply(input, options)
+	if err != nil || len(workingSlice) == 0 {
+		return zero, false, err
+	}
+
+	return workingSlice[len(workingSlice)-1], true, nil
+}
+
+// Whether every order can run one element at a time, see stream().
+func (pipeline *Pipeline[T]) streamable() bool {
+	for _, ord := range pipeline.orders {
+		switch ord.method {
+		case "filter", "map", "skip", "take":
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Push input through the orders one element at a time, handing each survivor to yield until it returns
+// false. Each element is cloned on its own just before the first Map touches it. Only for streamable()
+// pipelines.
+func (pipeline *Pipeline[T]) stream(input []T, options []Option, yield func(T) bool) {
+	cloneOptions := slices.Clone(options)
+	for idx, opt := range cloneOptions {
+		if opt == Opt_ClonePool {
+			cloneOptions[idx] = Opt_Clone // a lone element has no buffer worth pooling
+		}
+	}
+
+	reached := make([]int, len(pipeline.orders)) // elements that got to each order so far
+
+	for _, val := range input {
+		alive, cloned := true, false
+
+		for pos, ord := range pipeline.orders {
+			seen := reached[pos]
+			reached[pos]++
+
+			switch ord.method {
+			case "filter":
+				alive = pipeline.filterInstructs[ord.index](val)
+			case "map":
+				if !cloned {
+					val, cloned = pipeline.cloneInput([]T{val}, cloneOptions)[0], true
+				}
+				val = pipeline.mapInstructs[ord.index](seen, val)
+			case "skip":
+				alive = seen >= pipeline.skipCounts[ord.index]
+			case "take":
+				if seen >= pipeline.takeCounts[ord.index] {
+					return // nothing else can get past this point
+				}
+			}
+
+			if !alive {
+				break
+			}
+		}
+
+		if !alive {
+			continue
+		}
+
+		if !cloned {
+			val = pipeline.cloneInput([]T{val}, cloneOptions)[0]
+		}
+
+		if !yield(val) {
+			return
+		}
+	}
+}
+
+// Run the pipeline and report whether target is in the result, comparing with eq so T needn't be comparable.
+// Stops at the first match under the same conditions as First().
+func (pipeline *Pipeline[T]) Contains(input []T, target T, eq func(a, b T) bool, options ...Option) (bool, error) {
+	idx, err := pipeline.indexOf(input, target, eq, options, "Contains")
+	return idx >= 0, err
+}
+
+// Run the pipeline and return the position of the first element of the result eq to target, or -1.
+// Stops at the first match under the same conditions as First().
+func (pipeline *Pipeline[T]) IndexOf(input []T, target T, eq func(a, b T) bool, options ...Option) (int, error) {
+	return pipeline.indexOf(input, target, eq, options, "IndexOf")
+}
+
+func (pipeline *Pipeline[T]) indexOf(input []T, target T, eq func(a, b T) bool, options []Option, caller string) (int, error) {
+	if eq == nil {
+		return -1, fmt.Errorf("%v(): %w", caller, ErrNilFunc)
+	}
+
+	match := func(val T) bool { return eq(val, target) }
+
+	if !pipeline.streamable() {
+		workingSlice, err := pipeline.apply(input, options)
+		if err != nil {
+			return -1, err
+		}
+		return slices.IndexFunc(workingSlice, match), nil
+	}
+
+	start := time.Now()
+	if err := pipeline.checkApply(input, options); err != nil {
+		return -1, err
+	}
+
+	defer func() {
+		pipeline.recordSince(start)
+		if slices.Contains(options, Opt_Reset) {
+			*pipeline = Pipeline[T]{}
+		}
+	}()
+
+	found, pos := -1, 0
+	pipeline.stream(input, options, func(val T) bool {
+		if match(val) {
+			found = pos
+			return false
+		}
+		pos++
+		return true
+	})
+
+	return found, nil
+}
@@ -0,0 +1,161 @@
+package derp
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestFirstLast(t *testing.T) {
+	var visited int
+
+	var pipeline Pipeline[int]
+	pipeline.Filter(func(value int) bool {
+		visited++
+		return value%7 == 0
+	})
+	pipeline.Map(func(index int, value int) int { return value*10 + index })
+	pipeline.Skip(1)
+
+	input := Generate(1000, func(index int) int { return index + 1 })
+
+	first, ok, err := pipeline.First(input)
+	if err != nil || !ok || first != 141 {
+		t.Errorf("TestFirstLast(); First() mismatch.\nExpected: [141 true <nil>] Got: [%v %v %v]\n", first, ok, err)
+	}
+	if visited != 14 {
+		t.Errorf("TestFirstLast(); First() should stop at the first survivor. Visited: [%v]\n", visited)
+	}
+
+	expected, err := pipeline.Apply(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last, ok, err := pipeline.Last(input)
+	if err != nil || !ok || last != expected[len(expected)-1] || first != expected[0] {
+		t.Errorf("TestFirstLast(); Last() mismatch.\nExpected: [%v] Got: [%v %v %v]\n", expected[len(expected)-1], last, ok, err)
+	}
+
+	var none Pipeline[int]
+	none.Filter(func(value int) bool { return value > 5000 })
+
+	if _, ok, err := none.First(input); ok || err != nil {
+		t.Errorf("TestFirstLast(); First() on an empty result. Got: [%v %v]\n", ok, err)
+	}
+	if _, ok, err := none.Last(input); ok || err != nil {
+		t.Errorf("TestFirstLast(); Last() on an empty result. Got: [%v %v]\n", ok, err)
+	}
+}
+
+func TestFirstImmutability(t *testing.T) {
+	input := []*int{new(int), new(int)}
+	*input[1] = 1
+
+	var pipeline Pipeline[*int]
+	pipeline.Map(func(index int, value *int) *int {
+		*value += 100
+		return value
+	})
+	pipeline.Filter(func(value *int) bool { return *value > 100 })
+
+	first, ok, err := pipeline.First(input)
+	if err != nil || !ok || *first != 101 {
+		t.Errorf("TestFirstImmutability(); value mismatch. Got: [%v %v]\n", ok, err)
+	}
+	if *input[0] != 0 || *input[1] != 1 {
+		t.Errorf("TestFirstImmutability(); input mutated. Got: [%v %v]\n", *input[0], *input[1])
+	}
+}
+
+func TestContainsIndexOf(t *testing.T) {
+	var visited int
+
+	var pipeline Pipeline[[]int] // not comparable, so eq does the work
+	pipeline.Filter(func(value []int) bool {
+		visited++
+		return value[0]%2 == 0
+	})
+
+	input := Generate(100, func(index int) []int { return []int{index} })
+	eq := func(a, b []int) bool { return a[0] == b[0] }
+
+	ok, err := pipeline.Contains(input, []int{10}, eq)
+	if err != nil || !ok {
+		t.Errorf("TestContainsIndexOf(); surviving value.\nExpected: [true <nil>] Got: [%v %v]\n", ok, err)
+	}
+	if visited != 11 {
+		t.Errorf("TestContainsIndexOf(); Contains() should stop at the match. Visited: [%v]\n", visited)
+	}
+
+	ok, err = pipeline.Contains(input, []int{11}, eq)
+	if err != nil || ok {
+		t.Errorf("TestContainsIndexOf(); filtered value.\nExpected: [false <nil>] Got: [%v %v]\n", ok, err)
+	}
+
+	idx, err := pipeline.IndexOf(input, []int{10}, eq)
+	if err != nil || idx != 5 {
+		t.Errorf("TestContainsIndexOf(); IndexOf() mismatch.\nExpected: [5] Got: [%v %v]\n", idx, err)
+	}
+
+	idx, _ = pipeline.IndexOf(input, []int{11}, eq)
+	if idx != -1 {
+		t.Errorf("TestContainsIndexOf(); IndexOf() mismatch.\nExpected: [-1] Got: [%v]\n", idx)
+	}
+
+	pipeline.SortByKeys([]func(a, b []int) int{func(a, b []int) int { return b[0] - a[0] }}) // no longer streamable
+	idx, _ = pipeline.IndexOf(input, []int{10}, eq)
+	if idx != 44 {
+		t.Errorf("TestContainsIndexOf(); sorted IndexOf() mismatch.\nExpected: [44] Got: [%v]\n", idx)
+	}
+
+	if _, err := pipeline.Contains(input, []int{10}, nil); !errors.Is(err, ErrNilFunc) {
+		t.Errorf("TestContainsIndexOf(); nil eq.\nExpected: [%v] Got: [%v]\n", ErrNilFunc, err)
+	}
+}
+
+func TestApplyFirst(t *testing.T) {
+	input := Range(100)
+	isEven := func(value int) bool { return value%2 == 0 }
+
+	var takeThenFilter Pipeline[int]
+	takeThenFilter.Take(5)
+	takeThenFilter.Filter(isEven)
+
+	gotten, err := takeThenFilter.Apply(input)
+	if err != nil || !slices.Equal(gotten, []int{0, 2, 4}) {
+		t.Errorf("TestApplyFirst(); Take->Filter mismatch.\nExpected: [[0 2 4]] Got: [%v %v]\n", gotten, err)
+	}
+
+	var visited int
+
+	var evens Pipeline[int]
+	evens.Filter(func(value int) bool {
+		visited++
+		return isEven(value)
+	})
+
+	gotten, err = evens.ApplyFirst(input, 5)
+	if err != nil || !slices.Equal(gotten, []int{0, 2, 4, 6, 8}) {
+		t.Errorf("TestApplyFirst(); first 5 evens mismatch.\nExpected: [[0 2 4 6 8]] Got: [%v %v]\n", gotten, err)
+	}
+	if visited != 9 {
+		t.Errorf("TestApplyFirst(); should stop after the 5th even. Visited: [%v]\n", visited)
+	}
+
+	// not streamable, same answer
+	evens.Shuffle(1)
+	evens.SortByKeys([]func(a, b int) int{func(a, b int) int { return a - b }})
+
+	gotten, err = evens.ApplyFirst(input, 5)
+	if err != nil || !slices.Equal(gotten, []int{0, 2, 4, 6, 8}) {
+		t.Errorf("TestApplyFirst(); fallback mismatch.\nExpected: [[0 2 4 6 8]] Got: [%v %v]\n", gotten, err)
+	}
+
+	if gotten, _ := evens.ApplyFirst(input, 500); len(gotten) != 50 {
+		t.Errorf("TestApplyFirst(); short result.\nExpected: [50 items] Got: [%v]\n", len(gotten))
+	}
+	if _, err := evens.ApplyFirst(input, -1); !errors.Is(err, ErrInvalidCount) {
+		t.Errorf("TestApplyFirst(); expected ErrInvalidCount. Got: [%v]\n", err)
+	}
+}
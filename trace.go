@@ -0,0 +1,149 @@
+package derp
+
+// Per-element audit trails.
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// What one order did to one element.
+type StageOutcome[T any] struct {
+	Order  int    // position in the pipeline, 0-based
+	Method string // adapter, eg. "filter"
+	Kept   bool   // false if this order removed the element
+	Before T
+	After  T
+}
+
+// Outcomes per input element: Trace[i] lists, in pipeline order, every order element i reached.
+// An element's list ends at the order that dropped it.
+type Trace[T any] [][]StageOutcome[T]
+
+// Return the position of the order that dropped input element index, and false if it survived.
+func (trace Trace[T]) DroppedAt(index int) (int, bool) {
+	outcomes := trace[index]
+	if len(outcomes) == 0 || outcomes[len(outcomes)-1].Kept {
+		return 0, false
+	}
+
+	return outcomes[len(outcomes)-1].Order, true
+}
+
+// Like Apply(), but also record what every order did to every element: kept or dropped, and its value before
+// and after. Serial and clones each value it records, so only for auditing and debugging.
+//
+// Only orders that handle elements one at a time can be traced: Filter, Map, Skip, Take and the Foreach
+// and Collect variants. Any other order, eg. SortByKeys or Reduce, is rejected up front with ErrStatefulOrder.
+// Under Opt_InPlace and Opt_MapInPlace recorded values aren't cloned, so pointer elements show their latest state.
+func (pipeline *Pipeline[T]) ApplyTrace(input []T, options ...Option) ([]T, Trace[T], error) {
+	for pos, ord := range pipeline.orders {
+		switch ord.method {
+		case "filter", "map", "skip", "take", "foreach", "foreachIndexed", "foreachAsync", "foreachCollect", "tryForeach", "collect":
+		default:
+			return nil, nil, fmt.Errorf("ApplyTrace(): order %v (%v): %w", pos, ord.method, ErrStatefulOrder)
+		}
+	}
+
+	start := time.Now()
+	if err := pipeline.checkApply(input, options); err != nil {
+		return nil, nil, err
+	}
+
+	cloneOptions := slices.Clone(options)
+	for idx, opt := range cloneOptions {
+		if opt == Opt_ClonePool {
+			cloneOptions[idx] = Opt_Clone // no buffer reuse while values are being kept around
+		}
+	}
+
+	snapshot := func(val T) T {
+		return pipeline.cloneInput([]T{val}, cloneOptions)[0]
+	}
+
+	workingSlice := pipeline.cloneInput(input, cloneOptions)
+	origins := Range(len(workingSlice)) // input index of each working element
+	trace := make(Trace[T], len(input))
+
+	record := func(idx int, outcome StageOutcome[T]) {
+		trace[origins[idx]] = append(trace[origins[idx]], outcome)
+	}
+
+	for pos, ord := range pipeline.orders {
+		switch ord.method {
+		case "filter":
+			workOrder := pipeline.filterInstructs[ord.index]
+
+			kept := 0
+			for idx, val := range workingSlice {
+				before := snapshot(val)
+				ok := workOrder(val)
+				record(idx, StageOutcome[T]{Order: pos, Method: ord.method, Kept: ok, Before: before, After: before})
+
+				if ok {
+					workingSlice[kept], origins[kept] = val, origins[idx]
+					kept++
+				}
+			}
+
+			workingSlice, origins = workingSlice[:kept], origins[:kept]
+
+		case "map":
+			workOrder := pipeline.mapInstructs[ord.index]
+
+			for idx, val := range workingSlice {
+				before := snapshot(val)
+				workingSlice[idx] = workOrder(idx, val)
+				record(idx, StageOutcome[T]{Order: pos, Method: ord.method, Kept: true, Before: before, After: snapshot(workingSlice[idx])})
+			}
+
+		case "skip", "take":
+			n := min(pipeline.skipOrTake(ord), len(workingSlice))
+
+			for idx, val := range workingSlice {
+				before := snapshot(val)
+				kept := idx >= n
+				if ord.method == "take" {
+					kept = idx < n
+				}
+				record(idx, StageOutcome[T]{Order: pos, Method: ord.method, Kept: kept, Before: before, After: before})
+			}
+
+			if ord.method == "skip" {
+				workingSlice, origins = workingSlice[n:], origins[n:]
+			} else {
+				workingSlice, origins = workingSlice[:n], origins[:n]
+			}
+
+		default: // side effects only; the elements come out as they went in
+			var err error
+			workingSlice, err = pipeline.runRange(workingSlice, options, pos, pos+1, false)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			for idx, val := range workingSlice {
+				before := snapshot(val)
+				record(idx, StageOutcome[T]{Order: pos, Method: ord.method, Kept: true, Before: before, After: before})
+			}
+		}
+	}
+
+	pipeline.recordSince(start)
+
+	if slices.Contains(options, Opt_Reset) {
+		*pipeline = Pipeline[T]{}
+	}
+
+	return workingSlice, trace, nil
+}
+
+// The count a Skip or Take order was given.
+func (pipeline *Pipeline[T]) skipOrTake(ord order) int {
+	if ord.method == "skip" {
+		return pipeline.skipCounts[ord.index]
+	}
+
+	return pipeline.takeCounts[ord.index]
+}
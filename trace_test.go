@@ -0,0 +1,54 @@
+package derp
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestApplyTrace(t *testing.T) {
+	var pipeline Pipeline[int]
+	pipeline.Map(func(index int, value int) int { return value * 10 })
+	pipeline.Filter(func(value int) bool { return value >= 20 }, "at least 20")
+	pipeline.Foreach(func(value int) {})
+	pipeline.Take(2)
+
+	input := []int{1, 2, 3, 4}
+
+	gotten, trace, err := pipeline.ApplyTrace(input)
+	if err != nil {
+		t.Fatalf("TestApplyTrace(); error from ApplyTrace(): %v", err)
+	}
+
+	if expected, _ := pipeline.Apply(input); !slices.Equal(gotten, expected) {
+		t.Errorf("TestApplyTrace(); value mismatch with Apply().\nExpected: [%v] Got: [%v]\n", expected, gotten)
+	}
+
+	// 1 is mapped to 10, then dropped by the filter
+	expected := []StageOutcome[int]{
+		{Order: 0, Method: "map", Kept: true, Before: 1, After: 10},
+		{Order: 1, Method: "filter", Kept: false, Before: 10, After: 10},
+	}
+	if !slices.Equal(trace[0], expected) {
+		t.Errorf("TestApplyTrace(); dropped element mismatch.\nExpected: [%v] Got: [%v]\n", expected, trace[0])
+	}
+	if order, dropped := trace.DroppedAt(0); !dropped || order != 1 {
+		t.Errorf("TestApplyTrace(); DroppedAt(0) mismatch.\nExpected: [1 true] Got: [%v %v]\n", order, dropped)
+	}
+
+	// 4 makes it through the filter but is third in line for Take(2)
+	if order, dropped := trace.DroppedAt(3); !dropped || order != 3 {
+		t.Errorf("TestApplyTrace(); DroppedAt(3) mismatch.\nExpected: [3 true] Got: [%v %v]\n", order, dropped)
+	}
+	if _, dropped := trace.DroppedAt(1); dropped || len(trace[1]) != 4 {
+		t.Errorf("TestApplyTrace(); survivor mismatch. Got: [%v]\n", trace[1])
+	}
+	if !slices.Equal(input, []int{1, 2, 3, 4}) {
+		t.Errorf("TestApplyTrace(); input mutated. Got: [%v]\n", input)
+	}
+
+	pipeline.SortByKeys([]func(a, b int) int{func(a, b int) int { return a - b }})
+	if _, _, err := pipeline.ApplyTrace(input); !errors.Is(err, ErrStatefulOrder) {
+		t.Errorf("TestApplyTrace(); expected ErrStatefulOrder. Got: [%v]\n", err)
+	}
+}